@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"flag"
+	"os"
+	"strings"
 
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -21,6 +23,8 @@ func InitConfig(logger *zap.Logger) {
 		logger.Panic("Fatal error config file", zap.Error(err))
 	}
 	viper.SetDefault("http_port", 8090)
+	// Proxies (IPs or CIDRs, space separated) allowed to set X-Forwarded-For, ClientIP ignores the header when empty
+	viper.SetDefault("TRUSTED_PROXIES", []string{})
 	viper.SetDefault("pg_host", "postgres")
 	viper.SetDefault("pg_port", 5432)
 	viper.SetDefault("pg_user", "postgres")
@@ -32,10 +36,72 @@ func InitConfig(logger *zap.Logger) {
 	viper.SetDefault("KEYCLOAK_CLIENT_ID", "deals")
 	viper.SetDefault("KEYCLOAK_CLIENT_SECRET", "5UOAC7zxygFkG6Qr6t1dsb5vOTpOHWTv")
 	viper.SetDefault("KEYCLOAK_REALM", "master")
-	viper.SetDefault("KEYCLOAK_DEFAULT_ROLE_ID", "ab6ccaee-6cba-4af8-b22b-96194097a0b4")
 	viper.SetDefault("KEYCLOAK_DEFAULT_ROLE_NAME", "user")
+	viper.SetDefault("KEYCLOAK_ADMIN_ROLE_NAME", "admin")
+	viper.SetDefault("KEYCLOAK_MODERATOR_ROLE_NAME", "moderator")
+	// Issuer and audience default to the realm URL and the client ID when left empty
+	viper.SetDefault("KEYCLOAK_ISSUER", "")
+	viper.SetDefault("KEYCLOAK_AUDIENCE", "")
+	viper.SetDefault("KEYCLOAK_JWKS_REFRESH_INTERVAL", "15m")
+	viper.SetDefault("KEYCLOAK_INTROSPECT", false)
 	viper.SetDefault("DEFAULT_QUOTA_PERDAY", 10)
+	// "smtp" delivers mail, "capture" only logs it and is refused without --dev
+	viper.SetDefault("MAILER", "smtp")
+	viper.SetDefault("SMTP_HOST", "localhost")
+	viper.SetDefault("SMTP_PORT", 25)
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("MAIL_FROM", "no-reply@deals.local")
+	viper.SetDefault("PASSWORD_RESET_URL", "http://localhost:8090/reset-password")
+	viper.SetDefault("PASSWORD_RESET_TOKEN_TTL", "30m")
+	viper.SetDefault("PASSWORD_RESET_LIMIT_PER_EMAIL", 3)
+	viper.SetDefault("PASSWORD_RESET_LIMIT_PER_IP", 10)
+	viper.SetDefault("PASSWORD_RESET_LIMIT_WINDOW", "1h")
+	viper.SetDefault("EMAIL_VERIFICATION_URL", "http://localhost:8090/verify-email")
+	viper.SetDefault("EMAIL_VERIFICATION_TOKEN_TTL", "24h")
+	viper.SetDefault("EMAIL_VERIFICATION_LIMIT_PER_EMAIL", 3)
+	viper.SetDefault("EMAIL_VERIFICATION_LIMIT_PER_IP", 10)
+	viper.SetDefault("EMAIL_VERIFICATION_LIMIT_WINDOW", "1h")
+	viper.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", "720h")
+	viper.SetDefault("ACCOUNT_DELETION_PURGE_INTERVAL", "1h")
+	viper.SetDefault("DATA_EXPORT_DIR", "exports")
+	viper.SetDefault("DATA_EXPORT_TTL", "48h")
+	viper.SetDefault("DATA_EXPORT_DOWNLOAD_URL", fmt.Sprintf("http://localhost:%d/v1/user/export/download", viper.GetInt("http_port")))
+	viper.SetDefault("DATA_EXPORT_QUEUE_SIZE", 100)
+	viper.SetDefault("DATA_EXPORT_PURGE_INTERVAL", "1h")
+	// Pending exports older than this are assumed stuck, keep it above the longest queue wait
+	viper.SetDefault("DATA_EXPORT_STALE_AFTER", "6h")
+	// Names this instance on the data exports it generates, their files are only on its disk
+	hostname, _ := os.Hostname()
+	viper.SetDefault("INSTANCE_ID", hostname)
 	fmt.Println("KEYCLOAK_URL:", viper.GetString("KEYCLOAK_URL"))	
+
+	if err := Validate(); err != nil {
+		logger.Panic("Invalid config", zap.Error(err))
+	}
+}
+
+// requiredSecrets sign tokens mailed to users, a default would be public and let anyone forge them
+// Each one must be different, so a leaked secret only exposes one kind of token.
+var requiredSecrets = []string{
+	"EMAIL_VERIFICATION_SECRET",
+	"DATA_EXPORT_SECRET",
+}
+
+// Validate checks the settings that have no safe default
+func Validate() error {
+	seen := map[string]string{}
+	for _, key := range requiredSecrets {
+		secret := strings.TrimSpace(viper.GetString(key))
+		if secret == "" {
+			return fmt.Errorf("%s must be set", key)
+		}
+		if other, ok := seen[secret]; ok {
+			return fmt.Errorf("%s must differ from %s", key, other)
+		}
+		seen[secret] = key
+	}
+	return nil
 }
 
 var ReadInConfig = func() error {
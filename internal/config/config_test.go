@@ -0,0 +1,49 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"deals_chatting_app_backend/internal/config"
+)
+
+func TestInitConfig_MissingSecret(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	config.ReadInConfig = func() error { return nil }
+
+	assert.Panics(t, func() { config.InitConfig(zap.NewNop()) })
+}
+
+func TestValidate(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	for name, tc := range map[string]struct {
+		verificationSecret string
+		exportSecret       string
+		wantErr            string
+	}{
+		"set":                  {verificationSecret: "a-long-random-secret", exportSecret: "another-random-secret"},
+		"empty":                {verificationSecret: "", exportSecret: "another-random-secret", wantErr: "EMAIL_VERIFICATION_SECRET"},
+		"blank":                {verificationSecret: "   ", exportSecret: "another-random-secret", wantErr: "EMAIL_VERIFICATION_SECRET"},
+		"export missing":       {verificationSecret: "a-long-random-secret", wantErr: "DATA_EXPORT_SECRET"},
+		"export shares secret": {verificationSecret: "a-long-random-secret", exportSecret: "a-long-random-secret", wantErr: "DATA_EXPORT_SECRET"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			viper.Reset()
+			viper.Set("EMAIL_VERIFICATION_SECRET", tc.verificationSecret)
+			viper.Set("DATA_EXPORT_SECRET", tc.exportSecret)
+
+			err := config.Validate()
+
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	"deals_chatting_app_backend/internal/constant"
+	"deals_chatting_app_backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type AccountController interface {
+	UpdateStatus(ctx *gin.Context)
+	Moderate(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	CancelDeletion(ctx *gin.Context)
+}
+
+type AccountControllerImpl struct {
+	accountService service.AccountService
+	accountDeletionService service.AccountDeletionService
+}
+
+func NewAccountController(accountService service.AccountService, accountDeletionService service.AccountDeletionService) AccountController {
+	return &AccountControllerImpl{
+		accountService: accountService,
+		accountDeletionService: accountDeletionService,
+	}
+}
+
+// UpdateStatus lets an admin move the account in the :id path parameter to another state
+func (ctrl *AccountControllerImpl) UpdateStatus(c *gin.Context) {
+	req := data.AccountStatusRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctrl.changeStatus(c, model.AccountStatus(req.Status), req.Reason, ctrl.accountService.Transition)
+}
+
+// Moderate lets a moderator suspend the account in the :id path parameter or reinstate it
+func (ctrl *AccountControllerImpl) Moderate(c *gin.Context) {
+	req := data.ModerationStatusRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctrl.changeStatus(c, model.AccountStatus(req.Status), req.Reason, ctrl.accountService.Moderate)
+}
+
+// CancelDeletion lets an admin restore the deleted account in the :id path parameter before it is purged
+func (ctrl *AccountControllerImpl) CancelDeletion(c *gin.Context) {
+	req := data.CancelDeletionRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctrl.changeStatus(c, model.AccountStatusActive, req.Reason, func(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+		return ctrl.accountDeletionService.Cancel(userID, reason, actorID, ctx)
+	})
+}
+
+type statusChange func(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error)
+
+func (ctrl *AccountControllerImpl) changeStatus(c *gin.Context, to model.AccountStatus, reason string, change statusChange) {
+	ctx := c.Request.Context()
+	actorID, exists := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !exists {
+		c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("Invalid user ID"))
+		return
+	}
+
+	res, err := change(userID, to, reason, &actorID, ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidStatusTransition) {
+			c.AbortWithError(http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, service.ErrPrivilegedAccount) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := data.CreateUserResponse{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload: data.UserResponse{
+			ID:         res.ID.String(),
+			Username:   res.Username,
+			Email:      res.Email,
+			IsVerified: res.IsVerified,
+			CreatedAt:  res.CreatedAt,
+			LastLogin:  res.LastLogin,
+			VerifiedAt: res.VerifiedAt,
+			Status:     string(res.Status),
+		},
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete soft-deletes the caller's account, their data is purged once the grace period is over
+func (ctrl *AccountControllerImpl) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, exists := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !exists {
+		c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+		return
+	}
+
+	res, err := ctrl.accountDeletionService.Delete(userID, ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := data.DeleteAccountResponse{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload: data.AccountDeletionResponse{
+			RequestedAt: res.RequestedAt,
+			PurgeAfter:  res.PurgeAfter,
+		},
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
@@ -0,0 +1,240 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"deals_chatting_app_backend/internal/controller"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/middleware"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	mockService "deals_chatting_app_backend/internal/service/mocks"
+)
+
+func newAccountStatusContext(w *httptest.ResponseRecorder, adminID, userID uuid.UUID, payload data.AccountStatusRequest) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(w)
+	prepareRequest(ctx, payload)
+	ctx.Request = ctx.Request.WithContext(context.WithValue(context.Background(), middleware.UserIDKey, adminID))
+	ctx.Params = gin.Params{{Key: "id", Value: userID.String()}}
+	return ctx
+}
+
+func TestUpdateAccountStatus_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	adminID := uuid.New()
+	user := model.User{ID: uuid.New(), Username: "user1", Status: model.AccountStatusSuspended}
+	reqPayload := data.AccountStatusRequest{Status: "suspended", Reason: "spam reports"}
+
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, adminID, user.ID, reqPayload)
+
+	mockAccountService.EXPECT().Transition(user.ID, model.AccountStatusSuspended, "spam reports", &adminID, gomock.Any()).Return(&user, nil)
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.UpdateStatus(ctx)
+
+	res := data.CreateUserResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, user.ID.String(), res.Payload.ID)
+	assert.Equal(t, "suspended", res.Payload.Status)
+}
+
+func TestUpdateAccountStatus_InvalidTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	reqPayload := data.AccountStatusRequest{Status: "active", Reason: "restore"}
+
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, adminID, userID, reqPayload)
+
+	mockAccountService.EXPECT().Transition(userID, model.AccountStatusActive, "restore", &adminID, gomock.Any()).Return(nil, service.ErrInvalidStatusTransition)
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.UpdateStatus(ctx)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdateAccountStatus_UnknownStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, uuid.New(), uuid.New(), data.AccountStatusRequest{Status: "frozen", Reason: "test"})
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.UpdateStatus(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateAccountStatus_Deleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	// Deleting has to schedule the purge, so it isn't a plain status change
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, uuid.New(), uuid.New(), data.AccountStatusRequest{Status: "deleted", Reason: "test"})
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.UpdateStatus(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestModerateAccount_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	moderatorID := uuid.New()
+	user := model.User{ID: uuid.New(), Username: "user1", Status: model.AccountStatusSuspended}
+	reqPayload := data.AccountStatusRequest{Status: "suspended", Reason: "spam reports"}
+
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, moderatorID, user.ID, reqPayload)
+
+	mockAccountService.EXPECT().Moderate(user.ID, model.AccountStatusSuspended, "spam reports", &moderatorID, gomock.Any()).Return(&user, nil)
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.Moderate(ctx)
+
+	res := data.CreateUserResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "suspended", res.Payload.Status)
+}
+
+func TestModerateAccount_PrivilegedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	moderatorID := uuid.New()
+	adminID := uuid.New()
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, moderatorID, adminID, data.AccountStatusRequest{Status: "suspended", Reason: "test"})
+
+	mockAccountService.EXPECT().Moderate(adminID, model.AccountStatusSuspended, "test", &moderatorID, gomock.Any()).Return(nil, service.ErrPrivilegedAccount)
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.Moderate(ctx)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestModerateAccount_AdminOnlyStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountService := mockService.NewMockAccountService(ctrl)
+
+	// Banning is left to admins
+	w := httptest.NewRecorder()
+	ctx := newAccountStatusContext(w, uuid.New(), uuid.New(), data.AccountStatusRequest{Status: "banned", Reason: "test"})
+
+	controller := controller.NewAccountController(mockAccountService, mockService.NewMockAccountDeletionService(ctrl))
+	controller.Moderate(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCancelAccountDeletion_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountDeletionService := mockService.NewMockAccountDeletionService(ctrl)
+
+	adminID := uuid.New()
+	user := model.User{ID: uuid.New(), Username: "user1", Status: model.AccountStatusActive}
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(w)
+	prepareRequest(ctx, data.CancelDeletionRequest{Reason: "asked support"})
+	ctx.Request = ctx.Request.WithContext(context.WithValue(context.Background(), middleware.UserIDKey, adminID))
+	ctx.Params = gin.Params{{Key: "id", Value: user.ID.String()}}
+
+	mockAccountDeletionService.EXPECT().Cancel(user.ID, "asked support", &adminID, gomock.Any()).Return(&user, nil)
+
+	controller := controller.NewAccountController(mockService.NewMockAccountService(ctrl), mockAccountDeletionService)
+	controller.CancelDeletion(ctx)
+
+	res := data.CreateUserResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "active", res.Payload.Status)
+}
+
+func TestDeleteAccount_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccountDeletionService := mockService.NewMockAccountDeletionService(ctrl)
+
+	userID := uuid.New()
+	requestedAt := time.Now()
+	deletion := model.AccountDeletion{UserID: userID, RequestedAt: requestedAt, PurgeAfter: requestedAt.Add(30 * 24 * time.Hour)}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest("DELETE", "/user/me", nil)
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+	mockAccountDeletionService.EXPECT().Delete(userID, gomock.Any()).Return(&deletion, nil)
+
+	controller := controller.NewAccountController(mockService.NewMockAccountService(ctrl), mockAccountDeletionService)
+	controller.Delete(ctx)
+
+	res := data.DeleteAccountResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.True(t, compareTimes(deletion.PurgeAfter, res.Payload.PurgeAfter))
+}
+
+func TestDeleteAccount_Unauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("DELETE", "/user/me", nil)
+
+	controller := controller.NewAccountController(mockService.NewMockAccountService(ctrl), mockService.NewMockAccountDeletionService(ctrl))
+	controller.Delete(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
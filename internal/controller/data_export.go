@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	"deals_chatting_app_backend/internal/constant"
+	"deals_chatting_app_backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type DataExportController interface {
+	Request(ctx *gin.Context)
+	Status(ctx *gin.Context)
+	Download(ctx *gin.Context)
+}
+
+type DataExportControllerImpl struct {
+	dataExportService service.DataExportService
+}
+
+func NewDataExportController(dataExportService service.DataExportService) DataExportController {
+	return &DataExportControllerImpl{
+		dataExportService: dataExportService,
+	}
+}
+
+// Request starts an export of the caller's data, its progress is polled with Status
+func (ctrl *DataExportControllerImpl) Request(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, exists := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !exists {
+		c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+		return
+	}
+
+	res, err := ctrl.dataExportService.Request(userID, ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyRequests) {
+			c.AbortWithError(http.StatusTooManyRequests, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, ctrl.toResponse(res, ctx))
+}
+
+func (ctrl *DataExportControllerImpl) Status(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, exists := ctx.Value(middleware.UserIDKey).(uuid.UUID)
+	if !exists {
+		c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+		return
+	}
+
+	exportID, err := uuid.Parse(c.Param("exportId"))
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("Invalid export ID"))
+		return
+	}
+
+	res, err := ctrl.dataExportService.Find(userID, exportID, ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrDataExportNotFound) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ctrl.toResponse(res, ctx))
+}
+
+// Download serves the zip behind a signed link, so it works from a browser without the access token
+func (ctrl *DataExportControllerImpl) Download(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("Download token is required"))
+		return
+	}
+
+	res, err := ctrl.dataExportService.Open(token, c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDownloadToken) {
+			c.AbortWithError(http.StatusGone, err)
+			return
+		}
+		if errors.Is(err, service.ErrDataExportElsewhere) {
+			c.AbortWithError(http.StatusConflict, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.FileAttachment(res.FilePath, fmt.Sprintf("data-export-%s.zip", res.CreatedAt.Format("2006-01-02")))
+}
+
+func (ctrl *DataExportControllerImpl) toResponse(export *model.DataExport, ctx context.Context) data.DataExportResponse {
+	return data.DataExportResponse{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload: data.DataExport{
+			ID:          export.ID.String(),
+			Status:      string(export.Status),
+			Error:       export.Error,
+			CreatedAt:   export.CreatedAt,
+			CompletedAt: export.CompletedAt,
+			ExpiresAt:   export.ExpiresAt,
+			DownloadURL: ctrl.dataExportService.DownloadURL(export),
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"deals_chatting_app_backend/internal/controller"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/middleware"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	mockService "deals_chatting_app_backend/internal/service/mocks"
+)
+
+func TestRequestDataExport_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDataExportService := mockService.NewMockDataExportService(ctrl)
+
+	userID := uuid.New()
+	export := model.DataExport{ID: uuid.New(), UserID: userID, Status: model.DataExportStatusPending}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest("POST", "/user/me/export", nil)
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+
+	mockDataExportService.EXPECT().Request(userID, gomock.Any()).Return(&export, nil)
+	mockDataExportService.EXPECT().DownloadURL(&export).Return("")
+
+	controller := controller.NewDataExportController(mockDataExportService)
+	controller.Request(ctx)
+
+	res := data.DataExportResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, export.ID.String(), res.Payload.ID)
+	assert.Equal(t, "pending", res.Payload.Status)
+	assert.Empty(t, res.Payload.DownloadURL)
+}
+
+func TestDataExportStatus_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDataExportService := mockService.NewMockDataExportService(ctrl)
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest("GET", "/user/me/export/"+exportID.String(), nil)
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+	ctx.Params = gin.Params{{Key: "exportId", Value: exportID.String()}}
+
+	mockDataExportService.EXPECT().Find(userID, exportID, gomock.Any()).Return(nil, service.ErrDataExportNotFound)
+
+	controller := controller.NewDataExportController(mockDataExportService)
+	controller.Status(ctx)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDownloadDataExport_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDataExportService := mockService.NewMockDataExportService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("GET", "/user/export/download?token=expired", nil)
+
+	mockDataExportService.EXPECT().Open("expired", gomock.Any()).Return(nil, service.ErrInvalidDownloadToken)
+
+	controller := controller.NewDataExportController(mockDataExportService)
+	controller.Download(ctx)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/service"
+	"deals_chatting_app_backend/internal/constant"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type EmailVerificationController interface {
+	Verify(ctx *gin.Context)
+	Resend(ctx *gin.Context)
+}
+
+type EmailVerificationControllerImpl struct {
+	emailVerificationService service.EmailVerificationService
+}
+
+func NewEmailVerificationController(emailVerificationService service.EmailVerificationService) EmailVerificationController {
+	return &EmailVerificationControllerImpl{
+		emailVerificationService: emailVerificationService,
+	}
+}
+
+func (ctrl *EmailVerificationControllerImpl) Verify(c *gin.Context) {
+	req := data.VerifyEmailRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := ctrl.emailVerificationService.Verify(&req, ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := data.CreateUserResponse{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload: data.UserResponse{
+			ID:         res.ID.String(),
+			Username:   res.Username,
+			Email:      res.Email,
+			IsVerified: res.IsVerified,
+			CreatedAt:  res.CreatedAt,
+			LastLogin:  res.LastLogin,
+			VerifiedAt: res.VerifiedAt,
+			Status:     string(res.Status),
+		},
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (ctrl *EmailVerificationControllerImpl) Resend(c *gin.Context) {
+	req := data.ResendVerificationRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := ctrl.emailVerificationService.Resend(&req, c.ClientIP(), ctx); err != nil {
+		if errors.Is(err, service.ErrTooManyRequests) {
+			c.AbortWithError(http.StatusTooManyRequests, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	// Same answer whether or not the email belongs to an unverified account
+	c.JSON(http.StatusOK, data.BaseResponse{
+		ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+		TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	})
+}
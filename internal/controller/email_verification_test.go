@@ -0,0 +1,113 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"deals_chatting_app_backend/internal/controller"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	mockService "deals_chatting_app_backend/internal/service/mocks"
+)
+
+func TestVerifyEmail_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmailVerificationService := mockService.NewMockEmailVerificationService(ctrl)
+
+	reqPayload := data.VerifyEmailRequest{
+		Token: "signed-token",
+	}
+
+	verifiedAt := time.Now()
+	verifiedUser := model.User{
+		ID:         uuid.New(),
+		Username:   "user1",
+		Email:      "test1@example.com",
+		IsVerified: true,
+		VerifiedAt: verifiedAt,
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/verify", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockEmailVerificationService.EXPECT().Verify(&reqPayload, gomock.Any()).Return(&verifiedUser, nil)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewEmailVerificationController(mockEmailVerificationService)
+	controller.Verify(ctx)
+
+	res := data.CreateUserResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, verifiedUser.ID.String(), res.Payload.ID)
+	assert.True(t, res.Payload.IsVerified)
+	assert.True(t, compareTimes(verifiedAt, res.Payload.VerifiedAt))
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmailVerificationService := mockService.NewMockEmailVerificationService(ctrl)
+
+	reqPayload := data.VerifyEmailRequest{
+		Token: "tampered-token",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/verify", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockEmailVerificationService.EXPECT().Verify(&reqPayload, gomock.Any()).Return(nil, service.ErrInvalidVerificationToken)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewEmailVerificationController(mockEmailVerificationService)
+	controller.Verify(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResendVerification_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEmailVerificationService := mockService.NewMockEmailVerificationService(ctrl)
+
+	reqPayload := data.ResendVerificationRequest{
+		Email: "test1@example.com",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/verify/resend", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockEmailVerificationService.EXPECT().Resend(&reqPayload, gomock.Any(), gomock.Any()).Return(service.ErrTooManyRequests)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewEmailVerificationController(mockEmailVerificationService)
+	controller.Resend(ctx)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -82,6 +82,30 @@ func (mr *MockUserControllerMockRecorder) Login(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserController)(nil).Login), ctx)
 }
 
+// Logout mocks base method.
+func (m *MockUserController) Logout(ctx *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Logout", ctx)
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserControllerMockRecorder) Logout(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserController)(nil).Logout), ctx)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserController) RefreshToken(ctx *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RefreshToken", ctx)
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserControllerMockRecorder) RefreshToken(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserController)(nil).RefreshToken), ctx)
+}
+
 // Signup mocks base method.
 func (m *MockUserController) Signup(ctx *gin.Context) {
 	m.ctrl.T.Helper()
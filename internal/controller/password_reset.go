@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/service"
+	"deals_chatting_app_backend/internal/constant"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PasswordResetController interface {
+	Forgot(ctx *gin.Context)
+	Reset(ctx *gin.Context)
+}
+
+type PasswordResetControllerImpl struct {
+	passwordResetService service.PasswordResetService
+}
+
+func NewPasswordResetController(passwordResetService service.PasswordResetService) PasswordResetController {
+	return &PasswordResetControllerImpl{
+		passwordResetService: passwordResetService,
+	}
+}
+
+func (ctrl *PasswordResetControllerImpl) Forgot(c *gin.Context) {
+	req := data.ForgotPasswordRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := ctrl.passwordResetService.Forgot(&req, c.ClientIP(), ctx); err != nil {
+		if errors.Is(err, service.ErrTooManyRequests) {
+			c.AbortWithError(http.StatusTooManyRequests, err)
+			return
+		}
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	// Same answer whether or not the email belongs to an account
+	c.JSON(http.StatusOK, data.BaseResponse{
+		ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+		TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	})
+}
+
+func (ctrl *PasswordResetControllerImpl) Reset(c *gin.Context) {
+	req := data.ResetPasswordRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := ctrl.passwordResetService.Reset(&req, c.ClientIP(), ctx); err != nil {
+		switch {
+		case errors.Is(err, service.ErrTooManyRequests):
+			c.AbortWithError(http.StatusTooManyRequests, err)
+		case errors.Is(err, service.ErrInvalidResetToken):
+			c.AbortWithError(http.StatusBadRequest, err)
+		default:
+			c.AbortWithError(http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, data.BaseResponse{
+		ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+		TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	})
+}
@@ -0,0 +1,142 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"deals_chatting_app_backend/internal/controller"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/service"
+	mockService "deals_chatting_app_backend/internal/service/mocks"
+)
+
+func TestForgotPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordResetService := mockService.NewMockPasswordResetService(ctrl)
+
+	reqPayload := data.ForgotPasswordRequest{
+		Email: "test1@example.com",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/password/forgot", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockPasswordResetService.EXPECT().Forgot(&reqPayload, gomock.Any(), gomock.Any()).Return(nil)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewPasswordResetController(mockPasswordResetService)
+	controller.Forgot(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestForgotPassword_InvalidEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordResetService := mockService.NewMockPasswordResetService(ctrl)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/password/forgot", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	prepareRequest(ctx, data.ForgotPasswordRequest{Email: "not-an-email"})
+
+	controller := controller.NewPasswordResetController(mockPasswordResetService)
+	controller.Forgot(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestForgotPassword_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordResetService := mockService.NewMockPasswordResetService(ctrl)
+
+	reqPayload := data.ForgotPasswordRequest{
+		Email: "test1@example.com",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/password/forgot", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockPasswordResetService.EXPECT().Forgot(&reqPayload, gomock.Any(), gomock.Any()).Return(service.ErrTooManyRequests)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewPasswordResetController(mockPasswordResetService)
+	controller.Forgot(ctx)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordResetService := mockService.NewMockPasswordResetService(ctrl)
+
+	reqPayload := data.ResetPasswordRequest{
+		Token:    "expired-token",
+		Password: "newpassword",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/password/reset", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockPasswordResetService.EXPECT().Reset(&reqPayload, gomock.Any(), gomock.Any()).Return(service.ErrInvalidResetToken)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewPasswordResetController(mockPasswordResetService)
+	controller.Reset(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordResetService := mockService.NewMockPasswordResetService(ctrl)
+
+	reqPayload := data.ResetPasswordRequest{
+		Token:    "valid-token",
+		Password: "newpassword",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/password/reset", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockPasswordResetService.EXPECT().Reset(&reqPayload, gomock.Any(), gomock.Any()).Return(nil)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller := controller.NewPasswordResetController(mockPasswordResetService)
+	controller.Reset(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
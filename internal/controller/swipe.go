@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"deals_chatting_app_backend/internal/data"
 	"deals_chatting_app_backend/internal/service"
@@ -17,6 +18,7 @@ import (
 
 type SwipeController interface {
     CreateSwipe(ctx *gin.Context)
+    FindLikesReceived(ctx *gin.Context)
 }
 
 type SwipeControllerImpl struct {
@@ -52,6 +54,10 @@ func (ctrl *SwipeControllerImpl) CreateSwipe(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	swipe, err := ctrl.swipeService.Create(&req, userID, ctx)
+	if errors.Is(err, service.ErrInvalidLikedElement) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -66,6 +72,9 @@ func (ctrl *SwipeControllerImpl) CreateSwipe(c *gin.Context) {
 			UserID:    		swipe.UserID.String(),
 			SwipedUserID:	swipe.SwipedUserID.String(),
 			IsLiked:		swipe.IsLiked,
+			LikedElement:	swipe.LikedElement,
+			LikedElementRef:	swipe.LikedElementRef,
+			Comment:		swipe.Comment,
 			CreatedAt:		swipe.CreatedAt,
 			UpdatedAt:		swipe.UpdatedAt,
 		},
@@ -73,3 +82,49 @@ func (ctrl *SwipeControllerImpl) CreateSwipe(c *gin.Context) {
 
 	c.JSON(http.StatusOK, swipeResponse)
 }
+
+func (ctrl *SwipeControllerImpl) FindLikesReceived(c *gin.Context) {
+	userID, exists := c.Request.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	// Get pagination parameters from context
+	limit := c.GetInt("limit")
+	offset := c.GetInt("offset")
+
+	ctx := c.Request.Context()
+	swipes, err := ctrl.swipeService.FindLikesReceived(ctx, userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	likes := []data.Swipe{}
+	for _, swipe := range swipes {
+		likes = append(likes, data.Swipe{
+			UserID:    		swipe.UserID.String(),
+			SwipedUserID:	swipe.SwipedUserID.String(),
+			IsLiked:		swipe.IsLiked,
+			LikedElement:	swipe.LikedElement,
+			LikedElementRef:	swipe.LikedElementRef,
+			Comment:		swipe.Comment,
+			CreatedAt:		swipe.CreatedAt,
+			UpdatedAt:		swipe.UpdatedAt,
+		})
+	}
+
+	response := data.SwipeResponseList{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload:      likes,
+		TotalRecords: int64(len(likes)),
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -22,6 +22,7 @@ import (
 	"deals_chatting_app_backend/internal/constant"
 	"deals_chatting_app_backend/internal/data"
 	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
 	mockService "deals_chatting_app_backend/internal/service/mocks"
 )
 
@@ -102,6 +103,36 @@ func TestCreateSwipe_Success(t *testing.T) {
 	assert.Equal(t, expectedResponse.Payload.IsLiked, response.Payload.IsLiked)
 }
 
+func TestCreateSwipe_InvalidLikedElement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	curUserID := uuid.New()
+	mockSwipeService := mockService.NewMockSwipeService(ctrl)
+
+	reqPayload := data.CreateSwipeRequest{
+		SwipedUserID:    uuid.New().String(),
+		IsLiked:         true,
+		LikedElement:    "photo",
+		LikedElementRef: "someone-elses-photo.jpg",
+	}
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/swipe", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, curUserID))
+	reqJSON, _ := json.Marshal(reqPayload)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(reqJSON))
+
+	mockSwipeService.EXPECT().Create(gomock.Any(), curUserID, gomock.Any()).Return(nil, service.ErrInvalidLikedElement)
+
+	controller := controller.NewSwipeController(mockSwipeService, validator.New())
+	controller.CreateSwipe(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestCreateSwipe_ValidationError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -176,3 +207,143 @@ func TestCreateSwipe_ServiceError(t *testing.T) {
 	// Check if the response contains the expected error message
 	assert.Contains(t, w.Body.String(), expectedErrMsg)
 }
+
+func TestCreateSwipe_WithComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	curUserID := uuid.New()
+	swipedUserID := uuid.New()
+
+	mockSwipeService := mockService.NewMockSwipeService(ctrl)
+	mockValidator := validator.New()
+
+	reqPayload := data.CreateSwipeRequest{
+		SwipedUserID:    swipedUserID.String(),
+		IsLiked:         true,
+		LikedElement:    "photo",
+		LikedElementRef: "picture",
+		Comment:         "Where was this taken?",
+	}
+
+	swipeSvc := model.Swipe{
+		UserID:          curUserID,
+		SwipedUserID:    swipedUserID,
+		IsLiked:         true,
+		LikedElement:    reqPayload.LikedElement,
+		LikedElementRef: reqPayload.LikedElementRef,
+		Comment:         reqPayload.Comment,
+	}
+
+	// Create a new HTTP request
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/swipe", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	// Set user ID in context
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, curUserID))
+
+	// Set request body
+	reqJSON, _ := json.Marshal(reqPayload)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(reqJSON))
+
+	mockSwipeService.EXPECT().Create(&reqPayload, curUserID, gomock.Any()).Return(&swipeSvc, nil)
+
+	control := controller.NewSwipeController(mockSwipeService, mockValidator)
+	control.CreateSwipe(ctx)
+
+	var response data.SwipeResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, reqPayload.LikedElement, response.Payload.LikedElement)
+	assert.Equal(t, reqPayload.LikedElementRef, response.Payload.LikedElementRef)
+	assert.Equal(t, reqPayload.Comment, response.Payload.Comment)
+}
+
+func TestCreateSwipe_ElementWithoutRef(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	curUserID := uuid.New()
+
+	mockSwipeService := mockService.NewMockSwipeService(ctrl)
+	mockValidator := validator.New()
+
+	reqPayload := data.CreateSwipeRequest{
+		SwipedUserID: uuid.New().String(),
+		IsLiked:      true,
+		LikedElement: "prompt",
+	}
+
+	// Create a new HTTP request
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/swipe", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	// Set user ID in context
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, curUserID))
+
+	// Set request body
+	reqJSON, _ := json.Marshal(reqPayload)
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(reqJSON))
+
+	control := controller.NewSwipeController(mockSwipeService, mockValidator)
+	control.CreateSwipe(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "LikedElementRef")
+}
+
+func TestFindLikesReceived_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	curUserID := uuid.New()
+	likerID := uuid.New()
+
+	mockSwipeService := mockService.NewMockSwipeService(ctrl)
+	mockValidator := validator.New()
+
+	likes := []model.Swipe{
+		{
+			UserID:          likerID,
+			SwipedUserID:    curUserID,
+			IsLiked:         true,
+			LikedElement:    "prompt",
+			LikedElementRef: "favourite-food",
+			Comment:         "Same here!",
+		},
+	}
+
+	// Create a new HTTP request
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("GET", "/swipe/received", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	// Set user ID and pagination in context
+	ctx.Request = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, curUserID))
+	ctx.Set("limit", 10)
+	ctx.Set("offset", 0)
+
+	mockSwipeService.EXPECT().FindLikesReceived(gomock.Any(), curUserID, 10, 0).Return(likes, nil)
+
+	control := controller.NewSwipeController(mockSwipeService, mockValidator)
+	control.FindLikesReceived(ctx)
+
+	var response data.SwipeResponseList
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, constant.PROCESS_STATUS_SUCCESS, response.BaseResponse.ProcessStatus)
+	assert.Equal(t, int64(1), response.TotalRecords)
+	assert.Equal(t, int32(10), response.Limit)
+	assert.Len(t, response.Payload, 1)
+	assert.Equal(t, likerID.String(), response.Payload[0].UserID)
+	assert.Equal(t, "prompt", response.Payload[0].LikedElement)
+	assert.Equal(t, "favourite-food", response.Payload[0].LikedElementRef)
+	assert.Equal(t, "Same here!", response.Payload[0].Comment)
+}
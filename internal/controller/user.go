@@ -1,9 +1,11 @@
 package controller
 
 import (
+    "errors"
     "fmt"
 	"net/http"
 	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
 	"deals_chatting_app_backend/internal/service"
 	"deals_chatting_app_backend/internal/constant"
 	"deals_chatting_app_backend/internal/middleware"
@@ -19,6 +21,8 @@ import (
 type UserController interface {
 	Signup(ctx *gin.Context)
 	Login(ctx *gin.Context)
+	RefreshToken(ctx *gin.Context)
+	Logout(ctx *gin.Context)
     CreateOrUpdateProfile(ctx *gin.Context)
     CreateOrUpdatePreferences(ctx *gin.Context)
     FindAll(ctx *gin.Context)
@@ -69,6 +73,7 @@ func (ctrl *UserControllerImpl) Signup(c *gin.Context) {
 		CreatedAt: res.CreatedAt,
 		LastLogin: res.LastLogin,
 		VerifiedAt: res.VerifiedAt,
+		Status:    string(res.Status),
 	}
 
     resp := data.CreateUserResponse{
@@ -93,31 +98,84 @@ func (ctrl *UserControllerImpl) Login(c *gin.Context) {
 	ctx := c.Request.Context()
 	res, err := ctrl.userService.Login(&req, ctx)
 	if err != nil {
+		if errors.Is(err, service.ErrAccountNotActive) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
         c.AbortWithError(http.StatusUnauthorized, err)
 		return
 	}
 
-    tokenResponse := data.TokenResponse{
-        Token: *res,
-    }
-    
     resp := data.UserLoginResponse{
 		BaseResponse: data.BaseResponse{
 			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
 			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
 		},
-		Payload: tokenResponse,
+		Payload: toTokenResponse(res),
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (ctrl *UserControllerImpl) RefreshToken(c *gin.Context) {
+	req := data.RefreshTokenRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := ctrl.userService.RefreshToken(&req, ctx)
+	if err != nil {
+		c.AbortWithError(http.StatusUnauthorized, err)
+		return
+	}
+
+	resp := data.UserLoginResponse{
+		BaseResponse: data.BaseResponse{
+			ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+			TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+		},
+		Payload: toTokenResponse(res),
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+func (ctrl *UserControllerImpl) Logout(c *gin.Context) {
+	req := data.LogoutRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := ctrl.userService.Logout(&req, ctx); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data.BaseResponse{
+		ProcessStatus: constant.PROCESS_STATUS_SUCCESS,
+		TxnRef:        trace.SpanFromContext(ctx).SpanContext().TraceID().String(),
+	})
+}
+
+func toTokenResponse(token *identity.Token) data.TokenResponse {
+	return data.TokenResponse{
+		AccessToken:      token.AccessToken,
+		RefreshToken:     token.RefreshToken,
+		ExpiresIn:        token.ExpiresIn,
+		RefreshExpiresIn: token.RefreshExpiresIn,
+	}
+}
+
 
 func (ctrl *UserControllerImpl) CreateOrUpdateProfile(c *gin.Context) {
     req := data.CreateOrUpdateProfileRequest{}
-	userID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, exists := c.Request.Context().Value(middleware.TargetUserIDKey).(uuid.UUID)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
 		return
 	}
 
@@ -163,9 +221,9 @@ func (ctrl *UserControllerImpl) CreateOrUpdateProfile(c *gin.Context) {
 
 func (ctrl *UserControllerImpl) CreateOrUpdatePreferences(c *gin.Context) {
     req := data.CreateOrUpdatePreferencesRequest{}
-	userID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	userID, exists := c.Request.Context().Value(middleware.TargetUserIDKey).(uuid.UUID)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
 		return
 	}
 
@@ -23,6 +23,7 @@ import (
 	"deals_chatting_app_backend/internal/data"
 	mockService "deals_chatting_app_backend/internal/service/mocks"
 	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/identity"
 )
 
 var (
@@ -194,7 +195,12 @@ func TestLogin_Success(t *testing.T) {
 		Password: "password",
 	}
 
-	token := "some-valid-token"
+	token := identity.Token{
+		AccessToken:      "some-valid-token",
+		RefreshToken:     "some-refresh-token",
+		ExpiresIn:        300,
+		RefreshExpiresIn: 1800,
+	}
 
 	exp := data.UserLoginResponse{
 		BaseResponse: data.BaseResponse{
@@ -202,7 +208,10 @@ func TestLogin_Success(t *testing.T) {
 			TxnRef:        "", // To be dynamically set
 		},
 		Payload: data.TokenResponse{
-			Token: token,
+			AccessToken:      token.AccessToken,
+			RefreshToken:     token.RefreshToken,
+			ExpiresIn:        token.ExpiresIn,
+			RefreshExpiresIn: token.RefreshExpiresIn,
 		},
 	}
 
@@ -260,6 +269,125 @@ func TestLogin_Failure(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestRefreshToken_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mockService.NewMockUserService(ctrl)
+
+	reqPayload := data.RefreshTokenRequest{
+		RefreshToken: "some-refresh-token",
+	}
+
+	token := identity.Token{
+		AccessToken:      "new-access-token",
+		RefreshToken:     "new-refresh-token",
+		ExpiresIn:        300,
+		RefreshExpiresIn: 1800,
+	}
+
+	controller := controller.NewUserController(mockUserService, mockValidator)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/token/refresh", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockUserService.EXPECT().RefreshToken(&reqPayload, gomock.Any()).Return(&token, nil)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller.RefreshToken(ctx)
+
+	res := data.UserLoginResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, token.AccessToken, res.Payload.AccessToken)
+	assert.Equal(t, token.RefreshToken, res.Payload.RefreshToken)
+	assert.Equal(t, token.ExpiresIn, res.Payload.ExpiresIn)
+	assert.Equal(t, token.RefreshExpiresIn, res.Payload.RefreshExpiresIn)
+}
+
+func TestRefreshToken_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mockService.NewMockUserService(ctrl)
+
+	reqPayload := data.RefreshTokenRequest{
+		RefreshToken: "expired-refresh-token",
+	}
+
+	controller := controller.NewUserController(mockUserService, mockValidator)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/token/refresh", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockUserService.EXPECT().RefreshToken(&reqPayload, gomock.Any()).Return(nil, errors.New("invalid_grant"))
+
+	prepareRequest(ctx, reqPayload)
+
+	controller.RefreshToken(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogout_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mockService.NewMockUserService(ctrl)
+
+	reqPayload := data.LogoutRequest{
+		RefreshToken: "some-refresh-token",
+	}
+
+	controller := controller.NewUserController(mockUserService, mockValidator)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/logout", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	mockUserService.EXPECT().Logout(&reqPayload, gomock.Any()).Return(nil)
+
+	prepareRequest(ctx, reqPayload)
+
+	controller.Logout(ctx)
+
+	res := data.BaseResponse{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, constant.PROCESS_STATUS_SUCCESS, res.ProcessStatus)
+}
+
+func TestLogout_MissingRefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mockService.NewMockUserService(ctrl)
+	controller := controller.NewUserController(mockUserService, mockValidator)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("POST", "/logout", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	prepareRequest(ctx, data.LogoutRequest{})
+
+	controller.Logout(ctx)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestFindAll_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -347,7 +475,7 @@ func TestCreateOrUpdateProfile_Success(t *testing.T) {
 	dataProfile := data.Profile{
 		UserID:    userID.String(),
 		Fullname:  "fullname",
-		Age:       34, // calculate based on the current year
+		Age:       profile.CalculateAge(),
 		Religion:  "catholic",
 		Gender:    "F",
 		Country:   "Indonesia",
@@ -371,13 +499,15 @@ func TestCreateOrUpdateProfile_Success(t *testing.T) {
 	req := httptest.NewRequest("PUT", "/user/1/profile/", nil)
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
-	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: profileUUIDString})
 	ctx.Request = req
 
 	mockUserService.EXPECT().CreateOrUpdateProfile(&reqPayload, profileUUID, gomock.Any()).Return(&profile, nil)
 
 	prepareRequest(ctx, reqPayload)
 
+	// Set the user resolved by UserOwnershipMiddleware in context
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), middleware.TargetUserIDKey, profileUUID))
+
 	controller.CreateOrUpdateProfile(ctx)
 
 	res := data.ProfileResponse{}
@@ -440,13 +570,15 @@ func TestCreateOrUpdatePreferences_Success(t *testing.T) {
 	req := httptest.NewRequest("PUT", "/user/1/preferences/", nil)
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
-	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: profileUUIDString})
 	ctx.Request = req
 
 	mockUserService.EXPECT().CreateOrUpdatePreferences(&reqPayload, profileUUID, gomock.Any()).Return(&dataPreferences, nil)
 
 	prepareRequest(ctx, reqPayload)
 
+	// Set the user resolved by UserOwnershipMiddleware in context
+	ctx.Request = ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), middleware.TargetUserIDKey, profileUUID))
+
 	controller.CreateOrUpdatePreferences(ctx)
 
 	res := data.PreferencesResponse{}
@@ -458,3 +590,23 @@ func TestCreateOrUpdatePreferences_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, exp, res)
 }
+
+func TestCreateOrUpdateProfile_NoTargetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mockService.NewMockUserService(ctrl)
+	controller := controller.NewUserController(mockUserService, mockValidator)
+
+	gin.SetMode(gin.TestMode)
+	req := httptest.NewRequest("PUT", "/user/me/profile/", nil)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = req
+
+	prepareRequest(ctx, data.CreateOrUpdateProfileRequest{})
+
+	controller.CreateOrUpdateProfile(ctx)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
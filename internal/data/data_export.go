@@ -0,0 +1,68 @@
+package data
+
+import (
+	"time"
+)
+
+type DataExport struct {
+	ID			string		`json:"id"`
+	Status		string		`json:"status"`
+	Error		string		`json:"error,omitempty"`
+	CreatedAt	time.Time	`json:"created_at"`
+	CompletedAt	*time.Time	`json:"completed_at,omitempty"`
+	ExpiresAt	*time.Time	`json:"expires_at,omitempty"`
+	DownloadURL	string		`json:"download_url,omitempty"`
+}
+
+type DataExportResponse struct {
+	BaseResponse
+	Payload DataExport `json:"payload"`
+}
+
+// ExportProfile is the stored profile, with the date of birth instead of the age shown to others
+type ExportProfile struct {
+	UserID		string		`json:"user_id"`
+	Fullname	string		`json:"fullname"`
+	DOB			time.Time	`json:"dob"`
+	Religion	string		`json:"religion"`
+	Gender		string		`json:"gender"`
+	Country		string		`json:"country"`
+	City		string		`json:"city"`
+	Picture		string		`json:"picture"`
+	CreatedAt	time.Time	`json:"created_at"`
+	UpdatedAt	time.Time	`json:"updated_at"`
+}
+
+// ExportStatusChange is one change of the account status, ChangedBy is "you", "staff" or "system"
+type ExportStatusChange struct {
+	FromStatus	string		`json:"from_status"`
+	ToStatus	string		`json:"to_status"`
+	Reason		string		`json:"reason"`
+	ChangedBy	string		`json:"changed_by"`
+	CreatedAt	time.Time	`json:"created_at"`
+}
+
+type ExportAccountDeletion struct {
+	RequestedAt	time.Time	`json:"requested_at"`
+	PurgeAfter	time.Time	`json:"purge_after"`
+}
+
+// ExportPasswordReset is a requested reset link, the token itself is only stored hashed and left out
+type ExportPasswordReset struct {
+	CreatedAt	time.Time	`json:"created_at"`
+	ExpiresAt	time.Time	`json:"expires_at"`
+	UsedAt		*time.Time	`json:"used_at,omitempty"`
+}
+
+// ExportManifest describes the files of a personal data export
+type ExportManifest struct {
+	UserID		string					`json:"user_id"`
+	GeneratedAt	time.Time				`json:"generated_at"`
+	Files		[]ExportManifestFile	`json:"files"`
+}
+
+type ExportManifestFile struct {
+	Name		string	`json:"name"`
+	Description	string	`json:"description"`
+	Records		int		`json:"records"`
+}
@@ -8,13 +8,20 @@ type Swipe struct {
 	UserID			string		`json:"user_id"`
 	SwipedUserID	string		`json:"swiped_user_id"`
 	IsLiked			bool		`json:"is_liked"`
+	LikedElement	string		`json:"liked_element,omitempty"`
+	LikedElementRef	string		`json:"liked_element_ref,omitempty"`
+	Comment			string		`json:"comment,omitempty"`
 	CreatedAt		time.Time	`json:"created_at"`
 	UpdatedAt		time.Time	`json:"updated_at"`
 }
 
+// CreateSwipeRequest optionally lets a like point at a specific photo or profile prompt and carry a short comment.
 type CreateSwipeRequest struct {
 	SwipedUserID	string `json:"swiped_user_id" binding:"required"`
 	IsLiked			bool `json:"is_liked" binding:"required"`
+	LikedElement	string `json:"liked_element" binding:"omitempty,oneof=photo prompt,excluded_unless=IsLiked true"`
+	LikedElementRef	string `json:"liked_element_ref" binding:"required_with=LikedElement,max=255"`
+	Comment			string `json:"comment" binding:"max=140,excluded_unless=IsLiked true"`
 }
 
 type SwipeResponse struct {
@@ -22,6 +29,14 @@ type SwipeResponse struct {
 	Payload	Swipe	`json:"payload"`
 }
 
+type SwipeResponseList struct {
+	BaseResponse
+	Payload      []Swipe `json:"payload"`
+	TotalRecords int64   `json:"total_records"`
+	Limit        int32   `json:"limit"`
+	Offset       int32   `json:"offset"`
+}
+
 // type SwipeProfile struct {
 // 	UserID		string		`json:"user_id"`
 // 	Fullname	string		`json:"fullname"`
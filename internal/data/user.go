@@ -23,6 +23,7 @@ type UserResponse struct {
 	VerifiedAt	time.Time	`json:"verified_at"`
 	LastLogin	time.Time	`json:"last_login"`
 	CreatedAt	time.Time	`json:"created_at"`
+	Status		string		`json:"status"`
 }
 
 type CreateUserResponse struct {
@@ -36,7 +37,35 @@ type UserLoginRequest struct {
 }
 
 type TokenResponse struct {
-	Token	string	`json:"token"`
+	AccessToken			string	`json:"access_token"`
+	RefreshToken		string	`json:"refresh_token"`
+	ExpiresIn			int		`json:"expires_in"`
+	RefreshExpiresIn	int		`json:"refresh_expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
 }
 
 type UserLoginResponse struct {
@@ -125,3 +154,29 @@ type CreateOrUpdatePreferencesRequest struct {
 	Country		string		`json:"country" binding:"required"`
 	City		string		`json:"city" binding:"required"`
 }
+
+// AccountStatusRequest leaves out deleted, deleting an account also schedules its purge and goes through the deletion endpoints
+type AccountStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=pending active suspended banned"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ModerationStatusRequest only allows the states moderators may move accounts to
+type ModerationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active suspended"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type CancelDeletionRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type AccountDeletionResponse struct {
+	RequestedAt	time.Time	`json:"requested_at"`
+	PurgeAfter	time.Time	`json:"purge_after"`
+}
+
+type DeleteAccountResponse struct {
+	BaseResponse
+	Payload AccountDeletionResponse `json:"payload"`
+}
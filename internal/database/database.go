@@ -21,3 +21,16 @@ var DatabaseConnection = func() *gorm.DB {
 var Open = func(dialector gorm.Dialector) (*gorm.DB, error) {
 	return gorm.Open(dialector, &gorm.Config{})
 }
+
+// MigrateAccountStatus carries the old is_active flag over to the status column and drops it
+func MigrateAccountStatus(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("users", "is_active") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE users SET status = 'active' WHERE is_active = true AND status = 'pending'").Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE users DROP COLUMN is_active").Error
+	})
+}
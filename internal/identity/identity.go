@@ -0,0 +1,54 @@
+package identity
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrUserNotFound = errors.New("identity: user not found")
+var ErrUserExists = errors.New("identity: user already exists")
+var ErrInvalidCredentials = errors.New("invalid username or password")
+var ErrInvalidToken = errors.New("token is invalid or has expired")
+
+// User is what we hand the identity provider when creating an account
+type User struct {
+	Username	string
+	Email		string
+	Enabled		bool
+}
+
+// Token is a token pair issued on login or refresh, expiries are in seconds
+type Token struct {
+	AccessToken			string
+	RefreshToken		string
+	ExpiresIn			int
+	RefreshExpiresIn	int
+}
+
+// Claims is what we read from a verified access token. Roles are realm roles,
+// ClientRoles the roles granted on this service's own client.
+type Claims struct {
+	Subject		string
+	Roles		[]string
+	ClientRoles	[]string
+}
+
+// IdentityProvider manages the accounts and sessions that log users in, so services don't depend on Keycloak itself
+type IdentityProvider interface {
+	CreateUser(ctx context.Context, user User) (string, error)
+	SetPassword(ctx context.Context, userID, password string) error
+	// DeleteUser returns ErrUserNotFound when the user is already gone
+	DeleteUser(ctx context.Context, userID string) error
+	SetEnabled(ctx context.Context, userID string, enabled bool) error
+	SetEmailVerified(ctx context.Context, userID string) error
+	AssignRole(ctx context.Context, userID, role string) error
+	// Roles lists the realm roles and this service's client roles assigned to the user
+	Roles(ctx context.Context, userID string) ([]string, error)
+
+	Login(ctx context.Context, username, password string) (*Token, error)
+	Refresh(ctx context.Context, refreshToken string) (*Token, error)
+	Logout(ctx context.Context, refreshToken string) error
+	// RevokeSessions ends every session of the user
+	RevokeSessions(ctx context.Context, userID string) error
+	VerifyToken(ctx context.Context, accessToken string) (*Claims, error)
+}
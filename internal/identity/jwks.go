@@ -0,0 +1,244 @@
+package identity
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// minForcedRefreshInterval stops tokens with unknown key IDs from hammering the JWKS endpoint
+const minForcedRefreshInterval = 10 * time.Second
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JWKSKeySet caches the signing keys of the realm and refreshes them in the background
+type JWKSKeySet struct {
+	url                string
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	client             *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+
+	// refreshMu lets only one fetch run at a time, lastAttempt is set before each fetch whether it succeeds or not
+	refreshMu   sync.Mutex
+	lastAttempt time.Time
+}
+
+func NewJWKSKeySet(url string, refreshInterval time.Duration) *JWKSKeySet {
+	return &JWKSKeySet{
+		url:                url,
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minForcedRefreshInterval,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		keys:               map[string]*rsa.PublicKey{},
+	}
+}
+
+// Start fetches the keys once and keeps refreshing them until ctx is done
+func (k *JWKSKeySet) Start(ctx context.Context) {
+	if err := k.Refresh(ctx); err != nil {
+		zap.L().Sugar().Warnf("Failed to fetch JWKS, retrying on first request: %s", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(k.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := k.Refresh(ctx); err != nil {
+					zap.L().Sugar().Warnf("Failed to refresh JWKS: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// Refresh replaces the cached keys with the ones currently published, keeping the old ones on failure
+func (k *JWKSKeySet) Refresh(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	return k.fetch(ctx)
+}
+
+// refreshIfStale refreshes unless a fetch was attempted within minRefreshInterval.
+// Callers that queued up behind a running fetch see its attempt and don't start their own.
+func (k *JWKSKeySet) refreshIfStale(ctx context.Context) error {
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	if time.Since(k.lastAttempt) < k.minRefreshInterval {
+		return nil
+	}
+	return k.fetch(ctx)
+}
+
+// fetch downloads the key set. Callers hold refreshMu.
+func (k *JWKSKeySet) fetch(ctx context.Context) error {
+	k.lastAttempt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected JWKS status %d", res.StatusCode)
+	}
+
+	keySet := jsonWebKeySet{}
+	if err := json.NewDecoder(res.Body).Decode(&keySet); err != nil {
+		return err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range keySet.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			zap.L().Sugar().Warnf("Skipping JWKS key %s: %s", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	return nil
+}
+
+// Key returns the key with the given ID, refreshing once when it is unknown to pick up rotated keys
+func (k *JWKSKeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if key, exists := k.cached(kid); exists {
+		return key, nil
+	}
+
+	if err := k.refreshIfStale(ctx); err != nil {
+		return nil, err
+	}
+	// Another request may have picked up the key while this one waited for the refresh
+	if key, exists := k.cached(kid); exists {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (k *JWKSKeySet) cached(kid string) (*rsa.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, exists := k.keys[kid]
+	return key, exists
+}
+
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// AccessTokenClaims holds the claims we read from a Keycloak access token
+type AccessTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string `json:"azp"`
+	RealmAccess     struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+	// ResourceAccess holds the client roles, keyed by client ID
+	ResourceAccess map[string]struct {
+		Roles []string `json:"roles"`
+	} `json:"resource_access"`
+}
+
+// TokenVerifier checks access tokens locally against the realm's signing keys
+type TokenVerifier struct {
+	keys     *JWKSKeySet
+	issuer   string
+	audience string
+}
+
+func NewTokenVerifier(keys *JWKSKeySet, issuer, audience string) *TokenVerifier {
+	return &TokenVerifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// Verify checks the signature, issuer, audience, expiry and subject of an access token
+func (v *TokenVerifier) Verify(ctx context.Context, accessToken string) (*AccessTokenClaims, error) {
+	claims := AccessTokenClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(v.issuer),
+	)
+	_, err := parser.ParseWithClaims(accessToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+
+	// Keycloak puts the requesting client in azp and only lists other clients in aud
+	if claims.AuthorizedParty != v.audience && !containsString(claims.Audience, v.audience) {
+		return nil, fmt.Errorf("token is not meant for %s", v.audience)
+	}
+
+	return &claims, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "http://keycloak.test/realms/deals"
+	testAudience = "deals"
+)
+
+// stubJWKS serves whichever keys it currently holds, like a realm certs endpoint
+type stubJWKS struct {
+	mu   sync.Mutex
+	keys map[string]*rsa.PrivateKey
+}
+
+func (s *stubJWKS) set(kid string, key *rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = map[string]*rsa.PrivateKey{kid: key}
+}
+
+func (s *stubJWKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keySet := jsonWebKeySet{}
+	for kid, key := range s.keys {
+		keySet.Keys = append(keySet.Keys, jsonWebKey{
+			Kid: kid,
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		})
+	}
+	json.NewEncoder(w).Encode(keySet)
+}
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func newTestClaims(subject string) AccessTokenClaims {
+	claims := AccessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testIssuer,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{"account"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		AuthorizedParty: testAudience,
+	}
+	claims.RealmAccess.Roles = []string{"user"}
+	return claims
+}
+
+func signTestToken(t *testing.T, kid string, key *rsa.PrivateKey, claims AccessTokenClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func newTestVerifier(t *testing.T, jwks *stubJWKS) *TokenVerifier {
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+	keySet := NewJWKSKeySet(server.URL, time.Hour)
+	keySet.minRefreshInterval = 0
+	return NewTokenVerifier(keySet, testIssuer, testAudience)
+}
+
+func TestTokenVerifier_Valid(t *testing.T) {
+	key := newTestKey(t)
+	jwks := &stubJWKS{}
+	jwks.set("key-1", key)
+	verifier := newTestVerifier(t, jwks)
+
+	subject := uuid.New().String()
+	claims, err := verifier.Verify(context.Background(), signTestToken(t, "key-1", key, newTestClaims(subject)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, subject, claims.Subject)
+	assert.Equal(t, []string{"user"}, claims.RealmAccess.Roles)
+}
+
+func TestTokenVerifier_Rejected(t *testing.T) {
+	key := newTestKey(t)
+	jwks := &stubJWKS{}
+	jwks.set("key-1", key)
+	verifier := newTestVerifier(t, jwks)
+
+	tests := map[string]func(claims *AccessTokenClaims){
+		"expired":        func(claims *AccessTokenClaims) { claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) },
+		"no expiry":      func(claims *AccessTokenClaims) { claims.ExpiresAt = nil },
+		"wrong issuer":   func(claims *AccessTokenClaims) { claims.Issuer = "http://evil.test/realms/deals" },
+		"wrong audience": func(claims *AccessTokenClaims) { claims.AuthorizedParty = "other-client" },
+		"no subject":     func(claims *AccessTokenClaims) { claims.Subject = "" },
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := newTestClaims(uuid.New().String())
+			mutate(&claims)
+			_, err := verifier.Verify(context.Background(), signTestToken(t, "key-1", key, claims))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTokenVerifier_AudienceFromAud(t *testing.T) {
+	key := newTestKey(t)
+	jwks := &stubJWKS{}
+	jwks.set("key-1", key)
+	verifier := newTestVerifier(t, jwks)
+
+	claims := newTestClaims(uuid.New().String())
+	claims.AuthorizedParty = "other-client"
+	claims.Audience = jwt.ClaimStrings{"account", testAudience}
+	_, err := verifier.Verify(context.Background(), signTestToken(t, "key-1", key, claims))
+
+	assert.NoError(t, err)
+}
+
+func TestTokenVerifier_WrongSignature(t *testing.T) {
+	jwks := &stubJWKS{}
+	jwks.set("key-1", newTestKey(t))
+	verifier := newTestVerifier(t, jwks)
+
+	_, err := verifier.Verify(context.Background(), signTestToken(t, "key-1", newTestKey(t), newTestClaims(uuid.New().String())))
+
+	assert.Error(t, err)
+}
+
+func TestTokenVerifier_KeyRotation(t *testing.T) {
+	oldKey := newTestKey(t)
+	newKey := newTestKey(t)
+	jwks := &stubJWKS{}
+	jwks.set("key-1", oldKey)
+	verifier := newTestVerifier(t, jwks)
+
+	// Warm the cache with the old key
+	_, err := verifier.Verify(context.Background(), signTestToken(t, "key-1", oldKey, newTestClaims(uuid.New().String())))
+	assert.NoError(t, err)
+
+	// The realm rotates its key, tokens signed with the new one are picked up without waiting for the refresh
+	jwks.set("key-2", newKey)
+	_, err = verifier.Verify(context.Background(), signTestToken(t, "key-2", newKey, newTestClaims(uuid.New().String())))
+	assert.NoError(t, err)
+
+	// The old key is no longer published and is dropped
+	_, err = verifier.Verify(context.Background(), signTestToken(t, "key-1", oldKey, newTestClaims(uuid.New().String())))
+	assert.Error(t, err)
+}
+
+func TestJWKSKeySet_FailedRefreshThrottled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// Slow enough for concurrent requests to pile up behind the first fetch
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	keySet := NewJWKSKeySet(server.URL, time.Hour)
+	keySet.minRefreshInterval = time.Minute
+
+	// Tokens with unknown key IDs arriving together while the endpoint is down cause a single fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keySet.Key(context.Background(), "forged")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The failed attempt still counts, so later requests don't retry within minRefreshInterval
+	_, err := keySet.Key(context.Background(), "forged")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,213 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+)
+
+// Keycloak is the IdentityProvider backed by a Keycloak realm. Admin calls use the client's service account,
+// access tokens are verified locally and, with introspect set, also checked with Keycloak so revoked tokens are rejected.
+type Keycloak struct {
+	client       *gocloak.GoCloak
+	verifier     *TokenVerifier
+	clientID     string
+	clientSecret string
+	realm        string
+	introspect   bool
+}
+
+func NewKeycloak(client *gocloak.GoCloak, verifier *TokenVerifier, clientID, clientSecret, realm string, introspect bool) *Keycloak {
+	return &Keycloak{
+		client:       client,
+		verifier:     verifier,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		realm:        realm,
+		introspect:   introspect,
+	}
+}
+
+func (k *Keycloak) CreateUser(ctx context.Context, user User) (string, error) {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	userID, err := k.client.CreateUser(ctx, token, k.realm, gocloak.User{
+		Username: &user.Username,
+		Email:    &user.Email,
+		Enabled:  &user.Enabled,
+	})
+	if isStatus(err, http.StatusConflict) {
+		return "", ErrUserExists
+	}
+	return userID, err
+}
+
+func (k *Keycloak) SetPassword(ctx context.Context, userID, password string) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(k.client.SetPassword(ctx, token, userID, k.realm, password, false))
+}
+
+func (k *Keycloak) DeleteUser(ctx context.Context, userID string) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(k.client.DeleteUser(ctx, token, k.realm, userID))
+}
+
+func (k *Keycloak) SetEnabled(ctx context.Context, userID string, enabled bool) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(k.client.UpdateUser(ctx, token, k.realm, gocloak.User{ID: &userID, Enabled: &enabled}))
+}
+
+func (k *Keycloak) SetEmailVerified(ctx context.Context, userID string) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	emailVerified := true
+	return mapNotFound(k.client.UpdateUser(ctx, token, k.realm, gocloak.User{ID: &userID, EmailVerified: &emailVerified}))
+}
+
+// AssignRole adds the realm role with the given name to the user
+func (k *Keycloak) AssignRole(ctx context.Context, userID, role string) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	realmRole, err := k.client.GetRealmRole(ctx, token, k.realm, role)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(k.client.AddRealmRoleToUser(ctx, token, k.realm, userID, []gocloak.Role{*realmRole}))
+}
+
+// Roles lists the roles mapped to the user directly, roles only reached through a composite are not included
+func (k *Keycloak) Roles(ctx context.Context, userID string) ([]string, error) {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := k.client.GetRoleMappingByUserID(ctx, token, k.realm, userID)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+
+	roles := []string{}
+	appendRoles := func(mapped *[]gocloak.Role) {
+		if mapped == nil {
+			return
+		}
+		for _, role := range *mapped {
+			if role.Name != nil {
+				roles = append(roles, *role.Name)
+			}
+		}
+	}
+	appendRoles(mappings.RealmMappings)
+	for _, client := range mappings.ClientMappings {
+		if client != nil && client.Client != nil && *client.Client == k.clientID {
+			appendRoles(client.Mappings)
+		}
+	}
+	return roles, nil
+}
+
+func (k *Keycloak) Login(ctx context.Context, username, password string) (*Token, error) {
+	jwt, err := k.client.Login(ctx, k.clientID, k.clientSecret, k.realm, username, password)
+	if isStatus(err, http.StatusUnauthorized) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toToken(jwt), nil
+}
+
+func (k *Keycloak) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	jwt, err := k.client.RefreshToken(ctx, refreshToken, k.clientID, k.clientSecret, k.realm)
+	if isStatus(err, http.StatusBadRequest) || isStatus(err, http.StatusUnauthorized) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toToken(jwt), nil
+}
+
+// Logout ends the session of the refresh token. Access tokens of the session are only rejected
+// afterwards when introspect is set, the local check accepts them until they expire.
+func (k *Keycloak) Logout(ctx context.Context, refreshToken string) error {
+	return k.client.Logout(ctx, k.clientID, k.clientSecret, k.realm, refreshToken)
+}
+
+func (k *Keycloak) RevokeSessions(ctx context.Context, userID string) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(k.client.LogoutAllSessions(ctx, token, k.realm, userID))
+}
+
+func (k *Keycloak) VerifyToken(ctx context.Context, accessToken string) (*Claims, error) {
+	claims, err := k.verifier.Verify(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.introspect {
+		result, err := k.client.RetrospectToken(ctx, accessToken, k.clientID, k.clientSecret, k.realm)
+		if err != nil {
+			return nil, err
+		}
+		if result.Active == nil || !*result.Active {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	return &Claims{
+		Subject:     claims.Subject,
+		Roles:       claims.RealmAccess.Roles,
+		ClientRoles: claims.ResourceAccess[k.clientID].Roles,
+	}, nil
+}
+
+func (k *Keycloak) adminToken(ctx context.Context) (string, error) {
+	token, err := k.client.LoginClient(ctx, k.clientID, k.clientSecret, k.realm)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+func toToken(jwt *gocloak.JWT) *Token {
+	return &Token{
+		AccessToken:      jwt.AccessToken,
+		RefreshToken:     jwt.RefreshToken,
+		ExpiresIn:        jwt.ExpiresIn,
+		RefreshExpiresIn: jwt.RefreshExpiresIn,
+	}
+}
+
+func isStatus(err error, code int) bool {
+	var apiErr *gocloak.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func mapNotFound(err error) error {
+	if isStatus(err, http.StatusNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
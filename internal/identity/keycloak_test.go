@@ -0,0 +1,147 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubKeycloak records admin calls and answers them with the configured status, 204 by default
+type stubKeycloak struct {
+	mu       sync.Mutex
+	calls    []string
+	statuses map[string]int
+}
+
+func (s *stubKeycloak) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token") {
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("grant_type") == "password" && r.FormValue("password") != "password123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-token", "refresh_token": "refresh-token", "expires_in": 300})
+		return
+	}
+
+	call := r.Method + " " + r.URL.Path
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	status, exists := s.statuses[call]
+	s.mu.Unlock()
+	if !exists {
+		status = http.StatusNoContent
+	}
+	w.WriteHeader(status)
+}
+
+func (s *stubKeycloak) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+func newStubKeycloak(t *testing.T, statuses map[string]int) (*stubKeycloak, *Keycloak) {
+	stub := &stubKeycloak{statuses: statuses}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, NewKeycloak(gocloak.NewClient(server.URL), nil, "deals", "secret", "deals", false)
+}
+
+func TestKeycloak_DeleteUser(t *testing.T) {
+	stub, keycloak := newStubKeycloak(t, map[string]int{
+		"DELETE /admin/realms/deals/users/gone": http.StatusNotFound,
+	})
+
+	assert.NoError(t, keycloak.DeleteUser(context.Background(), "user1"))
+	assert.ErrorIs(t, keycloak.DeleteUser(context.Background(), "gone"), ErrUserNotFound)
+	assert.Equal(t, []string{
+		"DELETE /admin/realms/deals/users/user1",
+		"DELETE /admin/realms/deals/users/gone",
+	}, stub.Calls())
+}
+
+func TestKeycloak_RevokeSessions(t *testing.T) {
+	stub, keycloak := newStubKeycloak(t, nil)
+
+	assert.NoError(t, keycloak.RevokeSessions(context.Background(), "user1"))
+	assert.Equal(t, []string{"POST /admin/realms/deals/users/user1/logout"}, stub.Calls())
+}
+
+func TestKeycloak_Roles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/realms/deals/protocol/openid-connect/token":
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-token"})
+		case "/admin/realms/deals/users/user1/role-mappings":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"realmMappings": []map[string]string{{"name": "user"}},
+				"clientMappings": map[string]interface{}{
+					"deals": map[string]interface{}{"client": "deals", "mappings": []map[string]string{{"name": "moderator"}}},
+					"other": map[string]interface{}{"client": "other", "mappings": []map[string]string{{"name": "admin"}}},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		}
+	}))
+	t.Cleanup(server.Close)
+	keycloak := NewKeycloak(gocloak.NewClient(server.URL), nil, "deals", "secret", "deals", false)
+
+	// Roles on other clients are left out
+	roles, err := keycloak.Roles(context.Background(), "user1")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user", "moderator"}, roles)
+
+	_, err = keycloak.Roles(context.Background(), "gone")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestKeycloak_Login(t *testing.T) {
+	_, keycloak := newStubKeycloak(t, nil)
+
+	token, err := keycloak.Login(context.Background(), "user1", "password123")
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
+
+	_, err = keycloak.Login(context.Background(), "user1", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestKeycloak_VerifyToken(t *testing.T) {
+	key := newTestKey(t)
+	jwks := &stubJWKS{}
+	jwks.set("key-1", key)
+	keycloak := NewKeycloak(nil, newTestVerifier(t, jwks), "deals", "secret", "deals", false)
+	userID := uuid.New().String()
+
+	// Only roles on our own client are picked up
+	tokenClaims := newTestClaims(userID)
+	tokenClaims.ResourceAccess = map[string]struct {
+		Roles []string `json:"roles"`
+	}{
+		testAudience: {Roles: []string{"moderator"}},
+		"account":    {Roles: []string{"manage-account"}},
+	}
+
+	claims, err := keycloak.VerifyToken(context.Background(), signTestToken(t, "key-1", key, tokenClaims))
+	assert.NoError(t, err)
+	assert.Equal(t, userID, claims.Subject)
+	assert.Equal(t, []string{"user"}, claims.Roles)
+	assert.Equal(t, []string{"moderator"}, claims.ClientRoles)
+
+	_, err = keycloak.VerifyToken(context.Background(), signTestToken(t, "key-1", newTestKey(t), newTestClaims(userID)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,267 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	memoryAccessTokenTTL  = 5 * time.Minute
+	memoryRefreshTokenTTL = 30 * time.Minute
+)
+
+// MemoryUser is a snapshot of a user held by Memory
+type MemoryUser struct {
+	ID				string
+	Username		string
+	Email			string
+	Enabled			bool
+	EmailVerified	bool
+	Roles			[]string
+}
+
+type memoryUser struct {
+	MemoryUser
+	password string
+}
+
+type memoryToken struct {
+	sessionID string
+	expiresAt time.Time
+}
+
+// Memory is an IdentityProvider that keeps everything in process, for tests and dev mode.
+// Tokens are opaque random strings, nothing survives a restart.
+type Memory struct {
+	mu            sync.Mutex
+	users         map[string]*memoryUser
+	sessions      map[string]string
+	accessTokens  map[string]memoryToken
+	refreshTokens map[string]memoryToken
+	now           func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		users:         map[string]*memoryUser{},
+		sessions:      map[string]string{},
+		accessTokens:  map[string]memoryToken{},
+		refreshTokens: map[string]memoryToken{},
+		now:           time.Now,
+	}
+}
+
+// User returns a snapshot of the user with the given ID
+func (m *Memory) User(userID string) (MemoryUser, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, exists := m.users[userID]
+	if !exists {
+		return MemoryUser{}, false
+	}
+	snapshot := user.MemoryUser
+	snapshot.Roles = append([]string(nil), user.Roles...)
+	return snapshot, true
+}
+
+// Sessions counts the open sessions of the user
+func (m *Memory) Sessions(userID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, owner := range m.sessions {
+		if owner == userID {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *Memory) CreateUser(ctx context.Context, user User) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.users {
+		if existing.Username == user.Username {
+			return "", ErrUserExists
+		}
+	}
+	id := uuid.New().String()
+	m.users[id] = &memoryUser{MemoryUser: MemoryUser{
+		ID:       id,
+		Username: user.Username,
+		Email:    user.Email,
+		Enabled:  user.Enabled,
+	}}
+	return id, nil
+}
+
+func (m *Memory) SetPassword(ctx context.Context, userID, password string) error {
+	return m.update(userID, func(user *memoryUser) { user.password = password })
+}
+
+func (m *Memory) DeleteUser(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.users[userID]; !exists {
+		return ErrUserNotFound
+	}
+	delete(m.users, userID)
+	m.revoke(userID)
+	return nil
+}
+
+func (m *Memory) SetEnabled(ctx context.Context, userID string, enabled bool) error {
+	return m.update(userID, func(user *memoryUser) { user.Enabled = enabled })
+}
+
+func (m *Memory) SetEmailVerified(ctx context.Context, userID string) error {
+	return m.update(userID, func(user *memoryUser) { user.EmailVerified = true })
+}
+
+func (m *Memory) AssignRole(ctx context.Context, userID, role string) error {
+	return m.update(userID, func(user *memoryUser) {
+		for _, r := range user.Roles {
+			if r == role {
+				return
+			}
+		}
+		user.Roles = append(user.Roles, role)
+	})
+}
+
+func (m *Memory) Roles(ctx context.Context, userID string) ([]string, error) {
+	user, exists := m.User(userID)
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+	return user.Roles, nil
+}
+
+func (m *Memory) Login(ctx context.Context, username, password string) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Username == username {
+			if !user.Enabled || user.password == "" || user.password != password {
+				return nil, ErrInvalidCredentials
+			}
+			sessionID := uuid.New().String()
+			m.sessions[sessionID] = user.ID
+			return m.issue(sessionID)
+		}
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// Refresh rotates the refresh token, the old one can't be used again. Like Keycloak, disabled users can't refresh.
+func (m *Memory) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, exists := m.refreshTokens[refreshToken]
+	delete(m.refreshTokens, refreshToken)
+	if !exists || m.now().After(token.expiresAt) {
+		return nil, ErrInvalidToken
+	}
+	userID, exists := m.sessions[token.sessionID]
+	if !exists {
+		return nil, ErrInvalidToken
+	}
+	if user, exists := m.users[userID]; !exists || !user.Enabled {
+		return nil, ErrInvalidToken
+	}
+	return m.issue(token.sessionID)
+}
+
+func (m *Memory) Logout(ctx context.Context, refreshToken string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, exists := m.refreshTokens[refreshToken]
+	if !exists {
+		return ErrInvalidToken
+	}
+	delete(m.sessions, token.sessionID)
+	return nil
+}
+
+func (m *Memory) RevokeSessions(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.users[userID]; !exists {
+		return ErrUserNotFound
+	}
+	m.revoke(userID)
+	return nil
+}
+
+// VerifyToken rejects the tokens of disabled users, as Keycloak does with introspection
+func (m *Memory) VerifyToken(ctx context.Context, accessToken string) (*Claims, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, exists := m.accessTokens[accessToken]
+	if !exists || m.now().After(token.expiresAt) {
+		return nil, ErrInvalidToken
+	}
+	userID, exists := m.sessions[token.sessionID]
+	if !exists {
+		return nil, ErrInvalidToken
+	}
+	user, exists := m.users[userID]
+	if !exists || !user.Enabled {
+		return nil, ErrInvalidToken
+	}
+	return &Claims{Subject: user.ID, Roles: append([]string(nil), user.Roles...)}, nil
+}
+
+func (m *Memory) update(userID string, apply func(user *memoryUser)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, exists := m.users[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	apply(user)
+	return nil
+}
+
+// revoke drops the sessions of the user, the tokens of a missing session no longer verify. Callers hold mu.
+func (m *Memory) revoke(userID string) {
+	for sessionID, owner := range m.sessions {
+		if owner == userID {
+			delete(m.sessions, sessionID)
+		}
+	}
+}
+
+// issue hands out a new token pair for the session. Callers hold mu.
+func (m *Memory) issue(sessionID string) (*Token, error) {
+	accessToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	m.accessTokens[accessToken] = memoryToken{sessionID: sessionID, expiresAt: now.Add(memoryAccessTokenTTL)}
+	m.refreshTokens[refreshToken] = memoryToken{sessionID: sessionID, expiresAt: now.Add(memoryRefreshTokenTTL)}
+	return &Token{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int(memoryAccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int(memoryRefreshTokenTTL.Seconds()),
+	}, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
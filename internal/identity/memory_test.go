@@ -0,0 +1,138 @@
+package identity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newMemoryUser(t *testing.T, memory *Memory, username string) string {
+	id, err := memory.CreateUser(context.Background(), User{Username: username, Email: username + "@example.com", Enabled: true})
+	assert.NoError(t, err)
+	assert.NoError(t, memory.SetPassword(context.Background(), id, "password123"))
+	return id
+}
+
+func TestMemory_CreateUserExists(t *testing.T) {
+	memory := NewMemory()
+	newMemoryUser(t, memory, "user1")
+
+	_, err := memory.CreateUser(context.Background(), User{Username: "user1"})
+
+	assert.ErrorIs(t, err, ErrUserExists)
+}
+
+func TestMemory_Login(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	assert.NoError(t, memory.AssignRole(context.Background(), id, "admin"))
+
+	token, err := memory.Login(context.Background(), "user1", "password123")
+	assert.NoError(t, err)
+
+	claims, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, id, claims.Subject)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+
+	_, err = memory.Login(context.Background(), "user1", "wrong")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = memory.Login(context.Background(), "nobody", "password123")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestMemory_LoginDisabled(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	assert.NoError(t, memory.SetEnabled(context.Background(), id, false))
+
+	_, err := memory.Login(context.Background(), "user1", "password123")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestMemory_DisabledSessions(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+	assert.NoError(t, memory.SetEnabled(context.Background(), id, false))
+
+	// A suspended user can't keep using or refreshing a session they already had
+	_, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = memory.Refresh(context.Background(), token.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestMemory_RefreshRotates(t *testing.T) {
+	memory := NewMemory()
+	newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+
+	refreshed, err := memory.Refresh(context.Background(), token.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+
+	_, err = memory.Refresh(context.Background(), token.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestMemory_TokenExpiry(t *testing.T) {
+	memory := NewMemory()
+	newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+
+	now := time.Now().Add(memoryAccessTokenTTL + time.Second)
+	memory.now = func() time.Time { return now }
+
+	_, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = memory.Refresh(context.Background(), token.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestMemory_Logout(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+	other, _ := memory.Login(context.Background(), "user1", "password123")
+
+	assert.NoError(t, memory.Logout(context.Background(), token.RefreshToken))
+
+	_, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = memory.VerifyToken(context.Background(), other.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, memory.Sessions(id))
+}
+
+func TestMemory_RevokeSessions(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+
+	assert.NoError(t, memory.RevokeSessions(context.Background(), id))
+
+	assert.Equal(t, 0, memory.Sessions(id))
+	_, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = memory.Refresh(context.Background(), token.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, memory.RevokeSessions(context.Background(), "missing"), ErrUserNotFound)
+}
+
+func TestMemory_DeleteUser(t *testing.T) {
+	memory := NewMemory()
+	id := newMemoryUser(t, memory, "user1")
+	token, _ := memory.Login(context.Background(), "user1", "password123")
+
+	assert.NoError(t, memory.DeleteUser(context.Background(), id))
+
+	_, exists := memory.User(id)
+	assert.False(t, exists)
+	_, err := memory.VerifyToken(context.Background(), token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.ErrorIs(t, memory.DeleteUser(context.Background(), id), ErrUserNotFound)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RolesKey = "roles"
+const ClientRolesKey = "clientRoles"
+const TargetUserIDKey = "targetUserID"
+
+// MeAlias can be used in place of a user ID in the path to refer to the caller
+const MeAlias = "me"
+
+// HasRole checks whether the caller holds the given role, either as a realm role or as a role on our client
+func HasRole(ctx context.Context, role string) bool {
+	for _, key := range []string{RolesKey, ClientRolesKey} {
+		roles, _ := ctx.Value(key).([]string)
+		for _, r := range roles {
+			if r == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UserOwnershipMiddleware resolves the :id path parameter and only lets the request through
+// when it refers to the caller, or when the caller holds adminRole
+func UserOwnershipMiddleware(adminRole string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		callerID, exists := ctx.Value(UserIDKey).(uuid.UUID)
+		if !exists {
+			c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("User ID not found in context"))
+			return
+		}
+
+		targetID := callerID
+		if id := c.Param("id"); id != MeAlias {
+			parsedID, err := uuid.Parse(id)
+			if err != nil {
+				c.AbortWithError(http.StatusBadRequest, fmt.Errorf("Invalid user ID"))
+				return
+			}
+			targetID = parsedID
+		}
+
+		if targetID != callerID && !HasRole(ctx, adminRole) {
+			c.AbortWithError(http.StatusForbidden, fmt.Errorf("Not allowed to access this user"))
+			return
+		}
+
+		ctx = context.WithValue(ctx, TargetUserIDKey, targetID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireRole only lets callers holding at least one of roles through
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, role := range roles {
+			if HasRole(c.Request.Context(), role) {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithError(http.StatusForbidden, fmt.Errorf("Missing required role"))
+	}
+}
@@ -0,0 +1,151 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"deals_chatting_app_backend/internal/middleware"
+)
+
+// newOwnershipRouter builds a router that authenticates every request as callerID with the given roles
+func newOwnershipRouter(callerID uuid.UUID, roles []string, target *uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), middleware.UserIDKey, callerID)
+		ctx = context.WithValue(ctx, middleware.RolesKey, roles)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	router.PUT("/user/:id/profile", middleware.UserOwnershipMiddleware("admin"), func(c *gin.Context) {
+		*target = c.Request.Context().Value(middleware.TargetUserIDKey).(uuid.UUID)
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestUserOwnershipMiddleware_Self(t *testing.T) {
+	callerID := uuid.New()
+	var target uuid.UUID
+	router := newOwnershipRouter(callerID, nil, &target)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/"+callerID.String()+"/profile", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, callerID, target)
+}
+
+func TestUserOwnershipMiddleware_MeAlias(t *testing.T) {
+	callerID := uuid.New()
+	var target uuid.UUID
+	router := newOwnershipRouter(callerID, nil, &target)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/me/profile", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, callerID, target)
+}
+
+func TestUserOwnershipMiddleware_OtherUserForbidden(t *testing.T) {
+	var target uuid.UUID
+	router := newOwnershipRouter(uuid.New(), []string{"user"}, &target)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/"+uuid.New().String()+"/profile", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, uuid.Nil, target)
+}
+
+func TestUserOwnershipMiddleware_AdminAllowed(t *testing.T) {
+	otherID := uuid.New()
+	var target uuid.UUID
+	router := newOwnershipRouter(uuid.New(), []string{"user", "admin"}, &target)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/"+otherID.String()+"/profile", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, otherID, target)
+}
+
+func TestUserOwnershipMiddleware_InvalidID(t *testing.T) {
+	var target uuid.UUID
+	router := newOwnershipRouter(uuid.New(), nil, &target)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/user/not-a-uuid/profile", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for name, tc := range map[string]struct {
+		roles []string
+		code  int
+	}{
+		"admin":     {roles: []string{"user", "admin"}, code: http.StatusOK},
+		"user":      {roles: []string{"user"}, code: http.StatusForbidden},
+		"anonymous": {roles: nil, code: http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				ctx := context.WithValue(c.Request.Context(), middleware.RolesKey, tc.roles)
+				c.Request = c.Request.WithContext(ctx)
+				c.Next()
+			})
+			router.GET("/admin", middleware.RequireRole("admin"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func TestRequireRole_AnyOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for name, tc := range map[string]struct {
+		roles       []string
+		clientRoles []string
+		code        int
+	}{
+		"moderator":        {roles: []string{"user", "moderator"}, code: http.StatusOK},
+		"admin":            {roles: []string{"admin"}, code: http.StatusOK},
+		"client moderator": {roles: []string{"user"}, clientRoles: []string{"moderator"}, code: http.StatusOK},
+		"user":             {roles: []string{"user"}, clientRoles: []string{"uma_protection"}, code: http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				ctx := context.WithValue(c.Request.Context(), middleware.RolesKey, tc.roles)
+				ctx = context.WithValue(ctx, middleware.ClientRolesKey, tc.clientRoles)
+				c.Request = c.Request.WithContext(ctx)
+				c.Next()
+			})
+			router.GET("/moderation", middleware.RequireRole("moderator", "admin"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/moderation", nil))
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	"deals_chatting_app_backend/internal/constant"
 	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,7 +17,6 @@ import (
 	"github.com/spf13/viper"
     
 	"github.com/google/uuid"
-	gocloak "github.com/Nerzal/gocloak/v13"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
@@ -105,8 +106,11 @@ func Middleware(logger *zap.Logger) gin.HandlerFunc {
 		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(c.Writer.Status()))
 		// Log request and response
 		childLogger.Sugar().Infof("TraceID: %s - %d", sc.TraceID().String(), c.Writer.Status())
-		childLogger.Sugar().Infof("Request body: %s", requestBody)
-		childLogger.Sugar().Infof("Response body: %s", blw.body.String())
+		childLogger.Sugar().Infof("Request body: %s", redactBody(requestBody))
+		// Files such as data exports are not logged
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "application/json") {
+			childLogger.Sugar().Infof("Response body: %s", redactBody(blw.body.Bytes()))
+		}
 
 		if len(c.Errors) == 0 {
 			span.SetStatus(codes.Ok, "OK!")
@@ -128,8 +132,48 @@ func Middleware(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// redactedFields hold credentials, such as passwords, reset tokens and refresh tokens, that must not end up in the logs
+var redactedFields = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+}
+
+// redactBody masks the credentials in a JSON body for logging, bodies that aren't JSON objects are logged as they are
+func redactBody(body []byte) string {
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return string(body)
+	}
+	redacted, err := json.Marshal(redactValue(parsed))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[key] {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
 
-func KeycloakAuthMiddleware(client *gocloak.GoCloak, clientId, clientSecret, realm string) gin.HandlerFunc {
+// AuthMiddleware lets requests with an access token accepted by the identity provider through,
+// and puts the caller's ID and roles in the request context
+func AuthMiddleware(provider identity.IdentityProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -143,38 +187,21 @@ func KeycloakAuthMiddleware(client *gocloak.GoCloak, clientId, clientSecret, rea
 			return
 		}
 
-		accessToken := tokenParts[1]
-		ctx := c.Request.Context()
-		rptResult, err := client.RetrospectToken(ctx, accessToken, clientId, clientSecret, realm)
-		if err != nil {
-			c.AbortWithError(http.StatusBadRequest, err)
-			return
-		}
-
-		if !*rptResult.Active {
-			c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("Authorization token is invalid"))
-			return
-		}
-
-        userInfo, err := client.GetUserInfo(c, accessToken, realm)
+		claims, err := provider.VerifyToken(c.Request.Context(), tokenParts[1])
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unable to get user info"})
-			return
-		}
-        userSub := userInfo.Sub
-		if userSub == nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+			c.AbortWithError(http.StatusUnauthorized, fmt.Errorf("Authorization token is invalid: %w", err))
 			return
 		}
 
-		userID, err := uuid.Parse(*userSub)
+		userID, err := uuid.Parse(claims.Subject)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
 			return
 		}
 
-        fmt.Println("isisuususu", userID)
-		ctx = context.WithValue(c.Request.Context(), UserIDKey, userID)
+		ctx := context.WithValue(c.Request.Context(), UserIDKey, userID)
+		ctx = context.WithValue(ctx, RolesKey, claims.Roles)
+		ctx = context.WithValue(ctx, ClientRolesKey, claims.ClientRoles)
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
@@ -0,0 +1,88 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/middleware"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	provider := identity.NewMemory()
+	id, _ := provider.CreateUser(context.Background(), identity.User{Username: "user1", Enabled: true})
+	provider.SetPassword(context.Background(), id, "password123")
+	provider.AssignRole(context.Background(), id, "user")
+	token, _ := provider.Login(context.Background(), "user1", "password123")
+
+	var gotUserID uuid.UUID
+	var gotRoles []string
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", middleware.AuthMiddleware(provider), func(c *gin.Context) {
+		gotUserID = c.Request.Context().Value(middleware.UserIDKey).(uuid.UUID)
+		gotRoles = c.Request.Context().Value(middleware.RolesKey).([]string)
+		c.Status(http.StatusOK)
+	})
+
+	for name, tc := range map[string]struct {
+		header string
+		code   int
+	}{
+		"valid":   {header: "Bearer " + token.AccessToken, code: http.StatusOK},
+		"missing": {header: "", code: http.StatusUnauthorized},
+		"format":  {header: token.AccessToken, code: http.StatusBadRequest},
+		"invalid": {header: "Bearer not-a-token", code: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+
+	assert.Equal(t, uuid.MustParse(id), gotUserID)
+	assert.Equal(t, []string{"user"}, gotRoles)
+}
+
+func TestMiddleware_RedactsCredentials(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	previous := zap.L()
+	t.Cleanup(func() { zap.ReplaceGlobals(previous) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Middleware(zap.New(core)))
+	router.POST("/login", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"payload": gin.H{"access_token": "access-secret", "refresh_token": "refresh-secret", "expires_in": 300}})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"user1","password":"password-secret","token":"reset-secret"}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var logged strings.Builder
+	for _, entry := range logs.All() {
+		logged.WriteString(entry.Message + "\n")
+	}
+	for _, secret := range []string{"access-secret", "refresh-secret", "password-secret", "reset-secret"} {
+		assert.NotContains(t, logged.String(), secret)
+	}
+	// The rest of the bodies is still logged
+	assert.Contains(t, logged.String(), `"username":"user1"`)
+	assert.Contains(t, logged.String(), `"expires_in":300`)
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccountStatus string
+
+const (
+	AccountStatusPending   AccountStatus = "pending"
+	AccountStatusActive    AccountStatus = "active"
+	AccountStatusSuspended AccountStatus = "suspended"
+	AccountStatusBanned    AccountStatus = "banned"
+	AccountStatusDeleted   AccountStatus = "deleted"
+)
+
+// accountTransitions lists the states each state may move to, deleted is only left by cancelling the deletion, see CanRestoreTo
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatusPending:   {AccountStatusActive, AccountStatusBanned, AccountStatusDeleted},
+	AccountStatusActive:    {AccountStatusSuspended, AccountStatusBanned, AccountStatusDeleted},
+	AccountStatusSuspended: {AccountStatusActive, AccountStatusBanned, AccountStatusDeleted},
+	AccountStatusBanned:    {AccountStatusActive, AccountStatusDeleted},
+	AccountStatusDeleted:   {},
+}
+
+// moderatorTransitions is the part of the state machine moderators may use, everything else is left to admins
+var moderatorTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatusActive:    {AccountStatusSuspended},
+	AccountStatusSuspended: {AccountStatusActive},
+}
+
+func (s AccountStatus) IsValid() bool {
+	_, exists := accountTransitions[s]
+	return exists
+}
+
+// CanTransitionTo reports whether an account in state s may be moved to next
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	for _, allowed := range accountTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanModerateTo reports whether a moderator may move an account in state s to next
+func (s AccountStatus) CanModerateTo(next AccountStatus) bool {
+	for _, allowed := range moderatorTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRestoreTo reports whether cancelling a deletion may move an account in state s to next, deleted accounts become active again
+func (s AccountStatus) CanRestoreTo(next AccountStatus) bool {
+	return s == AccountStatusDeleted && next == AccountStatusActive
+}
+
+// AccountStatusTransition records who moved an account between states and why.
+// ActorID is nil when the system made the change, e.g. on email verification.
+type AccountStatusTransition struct {
+	gorm.Model
+	ID       	uuid.UUID		`gorm:"type:uuid;primary_key;not null;default:uuid_generate_v4()"`
+	UserID		uuid.UUID		`gorm:"type:uuid;not null;index"`
+	FromStatus	AccountStatus	`gorm:"type:varchar(20);not null"`
+	ToStatus	AccountStatus	`gorm:"type:varchar(20);not null"`
+	Reason		string			`gorm:"type:varchar(255);not null"`
+	ActorID		*uuid.UUID		`gorm:"type:uuid;default:null"`
+	CreatedAt	time.Time		`gorm:"autoCreateTime"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountDeletion tracks a user's deletion request until their data has been purged
+type AccountDeletion struct {
+	gorm.Model
+	ID       	uuid.UUID	`gorm:"type:uuid;primary_key;not null;default:uuid_generate_v4()"`
+	UserID		uuid.UUID	`gorm:"type:uuid;uniqueIndex;not null"`
+	RequestedAt	time.Time	`gorm:"autoCreateTime"`
+	PurgeAfter	time.Time	`gorm:"not null;index"`
+	CompletedAt	*time.Time	`gorm:"default:null"`
+	Attempts	int			`gorm:"not null;default:0"`
+	LastError	string		`gorm:"type:text"`
+}
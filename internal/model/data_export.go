@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending DataExportStatus = "pending"
+	DataExportStatusReady   DataExportStatus = "ready"
+	DataExportStatusFailed  DataExportStatus = "failed"
+	DataExportStatusExpired DataExportStatus = "expired"
+)
+
+// DataExport is a user's request for a copy of their personal data, FilePath points at the generated zip
+// on the local disk of Instance, the instance that generated it
+type DataExport struct {
+	gorm.Model
+	ID       	uuid.UUID			`gorm:"type:uuid;primary_key;not null;default:uuid_generate_v4()"`
+	UserID		uuid.UUID			`gorm:"type:uuid;not null;index"`
+	Status		DataExportStatus	`gorm:"type:varchar(20);not null;default:'pending';index"`
+	FilePath	string				`gorm:"type:varchar(255)"`
+	Instance	string				`gorm:"type:varchar(255)"`
+	Error		string				`gorm:"type:text"`
+	CreatedAt	time.Time			`gorm:"autoCreateTime"`
+	CompletedAt	*time.Time			`gorm:"default:null"`
+	ExpiresAt	*time.Time			`gorm:"default:null;index"`
+}
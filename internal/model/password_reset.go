@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken only keeps the SHA-256 hash of the token mailed to the user
+type PasswordResetToken struct {
+	gorm.Model
+	ID       	uuid.UUID	`gorm:"type:uuid;primary_key;not null;default:uuid_generate_v4()"`
+	UserID		uuid.UUID	`gorm:"type:uuid;not null;index"`
+	TokenHash	string		`gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt	time.Time	`gorm:"not null"`
+	UsedAt		*time.Time	`gorm:"default:null"`
+	CreatedAt	time.Time	`gorm:"autoCreateTime"`
+}
@@ -14,5 +14,8 @@ type Swipe struct {
 	UserID			uuid.UUID	`gorm:"type:uuid;not null"`
 	SwipedUserID	uuid.UUID	`gorm:"type:uuid;"`
 	CreatedAt		time.Time	`gorm:"autoCreateTime"`
-	IsLiked			bool		`gorm`
+	IsLiked			bool		`gorm:"default:false"`
+	LikedElement	string		`gorm:"type:varchar(20);"`
+	LikedElementRef	string		`gorm:"type:varchar(255);"`
+	Comment			string		`gorm:"type:varchar(140);"`
 }
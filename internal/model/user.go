@@ -17,7 +17,7 @@ type User struct {
 	VerifiedAt	time.Time	`gorm:"default:null"`
 	LastLogin	time.Time	`gorm:"autoUpdateTime"`
 	CreatedAt	time.Time	`gorm:"autoCreateTime"`
-	IsActive	bool       `gorm:"default:false"`
+	Status		AccountStatus	`gorm:"type:varchar(20);not null;default:'pending';index"`
 }
 
 type Profile struct {
@@ -49,6 +49,22 @@ type Preferences struct {
 	City		string		`gorm:"type:varchar(50);not null"`
 }
 
+// HasElement reports whether a like can point at the given element of the profile.
+// A photo is referenced by its picture URL, a prompt by the name of a filled-in profile field.
+func (p *Profile) HasElement(element, ref string) bool {
+	switch element {
+	case "photo":
+		return p.Picture != "" && ref == p.Picture
+	case "prompt":
+		prompts := map[string]string{
+			"religion": p.Religion,
+			"country":  p.Country,
+			"city":     p.City,
+		}
+		return prompts[ref] != ""
+	}
+	return false
+}
 
 func (p *Profile) CalculateAge() int {
 	now := time.Now()
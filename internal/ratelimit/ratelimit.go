@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type Limiter interface {
+	// Allow records a hit for key and reports whether it is still within the limit
+	Allow(key string) bool
+}
+
+// MemoryLimiter is a sliding window limiter kept in process memory
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	hits      map[string][]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter(limit int, window time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:  limit,
+		window: window,
+		hits:   map[string][]time.Time{},
+		now:    time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	cutoff := now.Add(-l.window)
+
+	// Drop keys nobody has hit for a whole window so the map doesn't grow forever
+	if now.Sub(l.lastSweep) > l.window {
+		for k, hits := range l.hits {
+			if len(hits) == 0 || !hits[len(hits)-1].After(cutoff) {
+				delete(l.hits, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	hits := l.hits[key]
+	recent := 0
+	for recent < len(hits) && !hits[recent].After(cutoff) {
+		recent++
+	}
+	hits = hits[recent:]
+
+	if len(hits) >= l.limit {
+		l.hits[key] = hits
+		return false
+	}
+
+	l.hits[key] = append(hits, now)
+	return true
+}
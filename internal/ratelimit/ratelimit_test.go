@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+
+	// Other keys have their own window
+	assert.True(t, limiter.Allow("b"))
+
+	// Hits slide out of the window one by one
+	now = now.Add(61 * time.Second)
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+}
+
+func TestMemoryLimiter_SweepsIdleKeys(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter(1, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("a")
+	now = now.Add(2 * time.Minute)
+	limiter.Allow("b")
+
+	assert.NotContains(t, limiter.hits, "a")
+	assert.Contains(t, limiter.hits, "b")
+}
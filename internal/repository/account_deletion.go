@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"deals_chatting_app_backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/google/uuid"
+)
+
+type AccountDeletionRepository interface {
+	Schedule(ctx context.Context, userID uuid.UUID, purgeAfter time.Time) (*model.AccountDeletion, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]model.AccountDeletion, error)
+	RecordFailure(ctx context.Context, id uuid.UUID, reason string) error
+	Purge(ctx context.Context, deletion model.AccountDeletion, now time.Time, removeExports func(exports []model.DataExport) error) error
+	Cancel(ctx context.Context, userID uuid.UUID) error
+}
+
+type AccountDeletionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAccountDeletionRepository(db *gorm.DB) AccountDeletionRepository {
+	return &AccountDeletionRepositoryImpl{DB: db}
+}
+
+// Schedule records the deletion request once, asking again returns the existing one
+func (r *AccountDeletionRepositoryImpl) Schedule(ctx context.Context, userID uuid.UUID, purgeAfter time.Time) (*model.AccountDeletion, error) {
+	deletion := model.AccountDeletion{UserID: userID, PurgeAfter: purgeAfter}
+	if err := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deletion).Error; err != nil {
+		return nil, err
+	}
+
+	var existing model.AccountDeletion
+	if err := r.DB.WithContext(ctx).First(&existing, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// FindDue fetches unfinished deletions whose grace period is over, including ones that failed before.
+// Accounts that are no longer deleted are skipped, their deletion was cancelled halfway.
+func (r *AccountDeletionRepositoryImpl) FindDue(ctx context.Context, now time.Time, limit int) ([]model.AccountDeletion, error) {
+	var deletions []model.AccountDeletion
+	err := r.DB.WithContext(ctx).
+		Joins("JOIN users ON users.id = account_deletions.user_id").
+		Where("account_deletions.completed_at IS NULL AND account_deletions.purge_after <= ?", now).
+		Where("users.status = ?", model.AccountStatusDeleted).
+		Order("account_deletions.purge_after").
+		Limit(limit).
+		Find(&deletions).Error
+	return deletions, err
+}
+
+func (r *AccountDeletionRepositoryImpl) RecordFailure(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.DB.WithContext(ctx).Model(&model.AccountDeletion{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": reason}).Error
+}
+
+// Purge hard-deletes everything stored for the user and marks the deletion completed, all in one transaction.
+// removeExports gets the user's data exports before the commit, an error from it rolls the purge back.
+// Deleting rows that are already gone is a no-op, so a purge that failed halfway can simply run again.
+func (r *AccountDeletionRepositoryImpl) Purge(ctx context.Context, deletion model.AccountDeletion, now time.Time, removeExports func(exports []model.DataExport) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		userID := deletion.UserID
+		var exports []model.DataExport
+		if err := tx.Unscoped().Where("user_id = ?", userID).Find(&exports).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.DataExport{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ? OR swiped_user_id = ?", userID, userID).Delete(&model.Swipe{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.Preferences{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.Profile{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		// The reasons are free text about the user, so the status history goes too
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.AccountStatusTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("id = ?", userID).Delete(&model.User{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AccountDeletion{}).
+			Where("id = ?", deletion.ID).
+			Updates(map[string]interface{}{"completed_at": now, "attempts": gorm.Expr("attempts + 1"), "last_error": ""}).Error; err != nil {
+			return err
+		}
+		return removeExports(exports)
+	})
+}
+
+// Cancel removes a deletion that hasn't been purged yet, so the user can delete their account again later
+func (r *AccountDeletionRepositoryImpl) Cancel(ctx context.Context, userID uuid.UUID) error {
+	return r.DB.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND completed_at IS NULL", userID).
+		Delete(&model.AccountDeletion{}).Error
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"deals_chatting_app_backend/internal/model"
+
+	"gorm.io/gorm"
+
+	"github.com/google/uuid"
+)
+
+// UserData is everything stored about a single user
+type UserData struct {
+	User			model.User
+	Profile			*model.Profile
+	Preferences		*model.Preferences
+	SwipesMade		[]model.Swipe
+	SwipesReceived	[]model.Swipe
+	StatusChanges	[]model.AccountStatusTransition
+	Deletion		*model.AccountDeletion
+	PasswordResets	[]model.PasswordResetToken
+}
+
+type DataExportRepository interface {
+	Save(ctx context.Context, export model.DataExport) (*model.DataExport, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*model.DataExport, error)
+	FindPendingByUserID(ctx context.Context, userID uuid.UUID) (*model.DataExport, error)
+	FindPending(ctx context.Context) ([]model.DataExport, error)
+	FindExpired(ctx context.Context, now time.Time) ([]model.DataExport, error)
+	Update(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error
+	FailPending(ctx context.Context, reason string, createdBefore time.Time) error
+	CollectUserData(ctx context.Context, userID uuid.UUID) (*UserData, error)
+}
+
+type DataExportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &DataExportRepositoryImpl{DB: db}
+}
+
+func (r *DataExportRepositoryImpl) Save(ctx context.Context, export model.DataExport) (*model.DataExport, error) {
+	if err := r.DB.WithContext(ctx).Create(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := r.DB.WithContext(ctx).First(&export, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindPendingByUserID fetches an export of the user that is still being generated
+func (r *DataExportRepositoryImpl) FindPendingByUserID(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := r.DB.WithContext(ctx).First(&export, "user_id = ? AND status = ?", userID, model.DataExportStatusPending).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindPending fetches every export still being generated, oldest first
+func (r *DataExportRepositoryImpl) FindPending(ctx context.Context) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.DB.WithContext(ctx).
+		Where("status = ?", model.DataExportStatusPending).
+		Order("created_at").
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepositoryImpl) FindExpired(ctx context.Context, now time.Time) ([]model.DataExport, error) {
+	var exports []model.DataExport
+	err := r.DB.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", model.DataExportStatusReady, now).
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepositoryImpl) Update(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	return r.DB.WithContext(ctx).Model(&model.DataExport{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// FailPending marks exports requested before createdBefore and still being generated as failed, their jobs were lost
+func (r *DataExportRepositoryImpl) FailPending(ctx context.Context, reason string, createdBefore time.Time) error {
+	return r.DB.WithContext(ctx).Model(&model.DataExport{}).
+		Where("status = ? AND created_at < ?", model.DataExportStatusPending, createdBefore).
+		Updates(map[string]interface{}{"status": model.DataExportStatusFailed, "error": reason}).Error
+}
+
+func (r *DataExportRepositoryImpl) CollectUserData(ctx context.Context, userID uuid.UUID) (*UserData, error) {
+	userData := UserData{}
+	db := r.DB.WithContext(ctx)
+
+	if err := db.First(&userData.User, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	var profile model.Profile
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&profile).Error; err != nil {
+		return nil, err
+	}
+	if profile.UserID == userID {
+		userData.Profile = &profile
+	}
+
+	var preferences model.Preferences
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	if preferences.UserID == userID {
+		userData.Preferences = &preferences
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&userData.SwipesMade).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("swiped_user_id = ?", userID).Order("created_at").Find(&userData.SwipesReceived).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&userData.StatusChanges).Error; err != nil {
+		return nil, err
+	}
+
+	var deletion model.AccountDeletion
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&deletion).Error; err != nil {
+		return nil, err
+	}
+	if deletion.UserID == userID {
+		userData.Deletion = &deletion
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("created_at").Find(&userData.PasswordResets).Error; err != nil {
+		return nil, err
+	}
+
+	return &userData, nil
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/account_deletion.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "deals_chatting_app_backend/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAccountDeletionRepository is a mock of AccountDeletionRepository interface.
+type MockAccountDeletionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionRepositoryMockRecorder
+}
+
+// MockAccountDeletionRepositoryMockRecorder is the mock recorder for MockAccountDeletionRepository.
+type MockAccountDeletionRepositoryMockRecorder struct {
+	mock *MockAccountDeletionRepository
+}
+
+// NewMockAccountDeletionRepository creates a new mock instance.
+func NewMockAccountDeletionRepository(ctrl *gomock.Controller) *MockAccountDeletionRepository {
+	mock := &MockAccountDeletionRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionRepository) EXPECT() *MockAccountDeletionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAccountDeletionRepository) Cancel(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Cancel(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Cancel), ctx, userID)
+}
+
+// FindDue mocks base method.
+func (m *MockAccountDeletionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now, limit)
+	ret0, _ := ret[0].([]model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockAccountDeletionRepositoryMockRecorder) FindDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockAccountDeletionRepository)(nil).FindDue), ctx, now, limit)
+}
+
+// Purge mocks base method.
+func (m *MockAccountDeletionRepository) Purge(ctx context.Context, deletion model.AccountDeletion, now time.Time, removeExports func([]model.DataExport) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletion, now, removeExports)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Purge(ctx, deletion, now, removeExports interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Purge), ctx, deletion, now, removeExports)
+}
+
+// RecordFailure mocks base method.
+func (m *MockAccountDeletionRepository) RecordFailure(ctx context.Context, id uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockAccountDeletionRepositoryMockRecorder) RecordFailure(ctx, id, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockAccountDeletionRepository)(nil).RecordFailure), ctx, id, reason)
+}
+
+// Schedule mocks base method.
+func (m *MockAccountDeletionRepository) Schedule(ctx context.Context, userID uuid.UUID, purgeAfter time.Time) (*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, userID, purgeAfter)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockAccountDeletionRepositoryMockRecorder) Schedule(ctx, userID, purgeAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockAccountDeletionRepository)(nil).Schedule), ctx, userID, purgeAfter)
+}
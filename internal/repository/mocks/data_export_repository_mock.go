@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/data_export.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "deals_chatting_app_backend/internal/model"
+	repository "deals_chatting_app_backend/internal/repository"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDataExportRepository is a mock of DataExportRepository interface.
+type MockDataExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryMockRecorder
+}
+
+// MockDataExportRepositoryMockRecorder is the mock recorder for MockDataExportRepository.
+type MockDataExportRepositoryMockRecorder struct {
+	mock *MockDataExportRepository
+}
+
+// NewMockDataExportRepository creates a new mock instance.
+func NewMockDataExportRepository(ctrl *gomock.Controller) *MockDataExportRepository {
+	mock := &MockDataExportRepository{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepository) EXPECT() *MockDataExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CollectUserData mocks base method.
+func (m *MockDataExportRepository) CollectUserData(ctx context.Context, userID uuid.UUID) (*repository.UserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectUserData", ctx, userID)
+	ret0, _ := ret[0].(*repository.UserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectUserData indicates an expected call of CollectUserData.
+func (mr *MockDataExportRepositoryMockRecorder) CollectUserData(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectUserData", reflect.TypeOf((*MockDataExportRepository)(nil).CollectUserData), ctx, userID)
+}
+
+// FailPending mocks base method.
+func (m *MockDataExportRepository) FailPending(ctx context.Context, reason string, createdBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailPending", ctx, reason, createdBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailPending indicates an expected call of FailPending.
+func (mr *MockDataExportRepositoryMockRecorder) FailPending(ctx, reason, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailPending", reflect.TypeOf((*MockDataExportRepository)(nil).FailPending), ctx, reason, createdBefore)
+}
+
+// FindByID mocks base method.
+func (m *MockDataExportRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockDataExportRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDataExportRepository)(nil).FindByID), ctx, id)
+}
+
+// FindExpired mocks base method.
+func (m *MockDataExportRepository) FindExpired(ctx context.Context, now time.Time) ([]model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, now)
+	ret0, _ := ret[0].([]model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired.
+func (mr *MockDataExportRepositoryMockRecorder) FindExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockDataExportRepository)(nil).FindExpired), ctx, now)
+}
+
+// FindPending mocks base method.
+func (m *MockDataExportRepository) FindPending(ctx context.Context) ([]model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx)
+	ret0, _ := ret[0].([]model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockDataExportRepositoryMockRecorder) FindPending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockDataExportRepository)(nil).FindPending), ctx)
+}
+
+// FindPendingByUserID mocks base method.
+func (m *MockDataExportRepository) FindPendingByUserID(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingByUserID", ctx, userID)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingByUserID indicates an expected call of FindPendingByUserID.
+func (mr *MockDataExportRepositoryMockRecorder) FindPendingByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingByUserID", reflect.TypeOf((*MockDataExportRepository)(nil).FindPendingByUserID), ctx, userID)
+}
+
+// Save mocks base method.
+func (m *MockDataExportRepository) Save(ctx context.Context, export model.DataExport) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, export)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockDataExportRepositoryMockRecorder) Save(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDataExportRepository)(nil).Save), ctx, export)
+}
+
+// Update mocks base method.
+func (m *MockDataExportRepository) Update(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDataExportRepositoryMockRecorder) Update(ctx, id, fields interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDataExportRepository)(nil).Update), ctx, id, fields)
+}
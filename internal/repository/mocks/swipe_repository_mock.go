@@ -36,6 +36,21 @@ func (m *MockSwipeRepository) EXPECT() *MockSwipeRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindLikesReceived mocks base method.
+func (m *MockSwipeRepository) FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLikesReceived", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]model.Swipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLikesReceived indicates an expected call of FindLikesReceived.
+func (mr *MockSwipeRepositoryMockRecorder) FindLikesReceived(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLikesReceived", reflect.TypeOf((*MockSwipeRepository)(nil).FindLikesReceived), ctx, userID, limit, offset)
+}
+
 // Save mocks base method.
 func (m *MockSwipeRepository) Save(ctx context.Context, userID uuid.UUID, swipe model.Swipe) (*model.Swipe, error) {
 	m.ctrl.T.Helper()
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "deals_chatting_app_backend/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -81,6 +82,21 @@ func (mr *MockUserRepositoryMockRecorder) FindAll(ctx, userID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepository)(nil).FindAll), ctx, userID)
 }
 
+// FindByEmail mocks base method.
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByEmail", ctx, email)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByEmail indicates an expected call of FindByEmail.
+func (mr *MockUserRepositoryMockRecorder) FindByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByEmail", reflect.TypeOf((*MockUserRepository)(nil).FindByEmail), ctx, email)
+}
+
 // FindByID mocks base method.
 func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +142,20 @@ func (mr *MockUserRepositoryMockRecorder) GetProfileByUserID(ctx, userID interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileByUserID", reflect.TypeOf((*MockUserRepository)(nil).GetProfileByUserID), ctx, userID)
 }
 
+// MarkVerified mocks base method.
+func (m *MockUserRepository) MarkVerified(ctx context.Context, userID uuid.UUID, verifiedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkVerified", ctx, userID, verifiedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkVerified indicates an expected call of MarkVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkVerified(ctx, userID, verifiedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkVerified), ctx, userID, verifiedAt)
+}
+
 // Save mocks base method.
 func (m *MockUserRepository) Save(ctx context.Context, user model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -140,3 +170,18 @@ func (mr *MockUserRepositoryMockRecorder) Save(ctx, user interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockUserRepository)(nil).Save), ctx, user)
 }
+
+// TransitionStatus mocks base method.
+func (m *MockUserRepository) TransitionStatus(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionStatus", ctx, transition, apply)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionStatus indicates an expected call of TransitionStatus.
+func (mr *MockUserRepositoryMockRecorder) TransitionStatus(ctx, transition, apply interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionStatus", reflect.TypeOf((*MockUserRepository)(nil).TransitionStatus), ctx, transition, apply)
+}
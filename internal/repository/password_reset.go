@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"deals_chatting_app_backend/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepository interface {
+	Save(ctx context.Context, token model.PasswordResetToken) (*model.PasswordResetToken, error)
+	Consume(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordResetToken, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+type PasswordResetRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &PasswordResetRepositoryImpl{DB: db}
+}
+
+func (r *PasswordResetRepositoryImpl) Save(ctx context.Context, token model.PasswordResetToken) (*model.PasswordResetToken, error) {
+	if err := r.DB.WithContext(ctx).Create(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Consume marks an unused, unexpired token as used in a single statement so it can only be redeemed once
+func (r *PasswordResetRepositoryImpl) Consume(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	result := r.DB.WithContext(ctx).Model(&token).Clauses(clause.Returning{}).
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Where("expires_at > ?", now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// InvalidateForUser marks every outstanding token of the user as used
+func (r *PasswordResetRepositoryImpl) InvalidateForUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	return r.DB.WithContext(ctx).Model(&model.PasswordResetToken{}).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Update("used_at", now).Error
+}
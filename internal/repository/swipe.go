@@ -11,6 +11,7 @@ import (
 
 type SwipeRepository interface {
 	Save(ctx context.Context, userID uuid.UUID, swipe model.Swipe) (*model.Swipe, error)
+	FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error)
 }
 
 type SwipeRepositoryImpl struct {
@@ -28,3 +29,31 @@ func (r *SwipeRepositoryImpl) Save(ctx context.Context, userID uuid.UUID, swipe
 	}
 	return &swipe, nil
 }
+
+// FindLikesReceived fetches the likes other active users gave the current user that the current user hasn't swiped back on yet
+func (r *SwipeRepositoryImpl) FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error) {
+	var swipes []model.Swipe
+
+	// Subquery to find a swipe of the current user on the liker
+	subQuery := r.DB.WithContext(ctx).Table("swipes AS replies").Select("1").
+		Where("replies.user_id = ? AND replies.swiped_user_id = swipes.user_id AND replies.deleted_at IS NULL", userID)
+
+	// Likes of deleted, suspended or banned users are hidden, like the users themselves
+	query := r.DB.WithContext(ctx).
+		Select("swipes.*").
+		Joins("JOIN users ON users.id = swipes.user_id").
+		Where("swipes.swiped_user_id = ?", userID).
+		Where("swipes.is_liked = ?", true).
+		Where("users.status = ? AND users.deleted_at IS NULL", model.AccountStatusActive).
+		Where("NOT EXISTS (?)", subQuery).
+		Order("swipes.created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	if err := query.Find(&swipes).Error; err != nil {
+		return nil, err
+	}
+	return swipes, nil
+}
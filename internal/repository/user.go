@@ -16,6 +16,9 @@ type UserRepository interface {
 	Save(ctx context.Context, user model.User) (*model.User, error)
 	FindByID(ctx context.Context, id string) (*model.User, error)
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
+	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	MarkVerified(ctx context.Context, userID uuid.UUID, verifiedAt time.Time) error
+	TransitionStatus(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error)
 	CreateOrUpdateProfile(ctx context.Context, userID uuid.UUID, profile model.Profile) (*model.Profile, error)
 	CreateOrUpdatePreferences(ctx context.Context, userID uuid.UUID, preferences model.Preferences) (*model.Preferences, error)
 	FindAll(ctx context.Context, userID uuid.UUID) ([]model.User, error)
@@ -40,7 +43,7 @@ func (r *UserRepositoryImpl) Save(ctx context.Context, user model.User) (*model.
 
 func (r *UserRepositoryImpl) FindByID(ctx context.Context, id string) (*model.User, error) {
 	var user model.User
-	if err := r.DB.WithContext(ctx).Preload("Profile").First(&user, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -60,6 +63,53 @@ func (r *UserRepositoryImpl) FindByUsername(ctx context.Context, username string
 	return &user, nil
 }
 
+func (r *UserRepositoryImpl) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	if err := r.DB.WithContext(ctx).First(&user, "email = ?", email).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepositoryImpl) MarkVerified(ctx context.Context, userID uuid.UUID, verifiedAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&model.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{"is_verified": true, "verified_at": verifiedAt}).Error
+}
+
+// TransitionStatus moves the user from transition.FromStatus to transition.ToStatus and records it.
+// It returns false without changing anything when the user is no longer in FromStatus.
+// apply runs inside the transaction once the rows are written, an error from it rolls them back.
+func (r *UserRepositoryImpl) TransitionStatus(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error) {
+	applied := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).
+			Where("id = ? AND status = ?", transition.UserID, transition.FromStatus).
+			Update("status", transition.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(&transition).Error; err != nil {
+			return err
+		}
+		if err := apply(); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
 func (r *UserRepositoryImpl) CreateOrUpdateProfile(ctx context.Context, userID uuid.UUID, newProfile model.Profile) (*model.Profile, error) {
 	var profile model.Profile
 	if err := r.DB.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error; err != nil {
@@ -154,7 +204,7 @@ func (r *UserRepositoryImpl) FindAll(ctx context.Context, userID uuid.UUID) ([]m
 		Joins("JOIN profiles ON users.id = profiles.user_id").
 		Where("users.id NOT IN (?)", subQuery).
 		Where("users.id <> ?", userID). // Exclude the current user
-		Where("users.is_active = ?", true)
+		Where("users.status = ?", model.AccountStatusActive)
 
 	// Apply age filtering if preferences exist
 	if preferencesExist {
@@ -8,19 +8,22 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	
-	gocloak "github.com/Nerzal/gocloak/v13"
 	"go.uber.org/zap"
 
 	"deals_chatting_app_backend/internal/controller"
+	"deals_chatting_app_backend/internal/identity"
 	"deals_chatting_app_backend/internal/middleware"
 )
 
-func NewRouter(keycloak *gocloak.GoCloak, userController controller.UserController, swipeController controller.SwipeController, logger *zap.Logger) *gin.Engine {
-	keycloakClientId := viper.GetString("KEYCLOAK_CLIENT_ID")
-	keycloakRealm := viper.GetString("KEYCLOAK_REALM")
-	keycloakClientSecret := viper.GetString("KEYCLOAK_CLIENT_SECRET")
+func NewRouter(identityProvider identity.IdentityProvider, userController controller.UserController, swipeController controller.SwipeController, passwordResetController controller.PasswordResetController, emailVerificationController controller.EmailVerificationController, accountController controller.AccountController, dataExportController controller.DataExportController, logger *zap.Logger) *gin.Engine {
+	adminRole := viper.GetString("KEYCLOAK_ADMIN_ROLE_NAME")
+	moderatorRole := viper.GetString("KEYCLOAK_MODERATOR_ROLE_NAME")
 
 	router := gin.Default()
+	// Rate limits key on ClientIP, so only proxies we run may override it
+	if err := router.SetTrustedProxies(viper.GetStringSlice("TRUSTED_PROXIES")); err != nil {
+		logger.Sugar().Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
 	router.Use(middleware.Middleware(logger))
 	router.Use(middleware.PaginationMiddleware())
 
@@ -38,18 +41,45 @@ func NewRouter(keycloak *gocloak.GoCloak, userController controller.UserControll
 	userRouter := v1Router.Group("/user")
 	userRouter.POST("/signup", userController.Signup)
 	userRouter.POST("/login", userController.Login)
+	userRouter.POST("/token/refresh", userController.RefreshToken)
+	userRouter.POST("/logout", userController.Logout)
+	userRouter.POST("/password/forgot", passwordResetController.Forgot)
+	userRouter.POST("/password/reset", passwordResetController.Reset)
+	userRouter.POST("/verify", emailVerificationController.Verify)
+	userRouter.POST("/verify/resend", emailVerificationController.Resend)
+	userRouter.GET("/export/download", dataExportController.Download)
 
-	// Apply Keycloak middleware to routes that require authentication
+	// Apply auth middleware to routes that require authentication
 	authenticatedUser := userRouter.Group("/")
-	authenticatedUser.Use(middleware.KeycloakAuthMiddleware(keycloak, keycloakClientId, keycloakClientSecret, keycloakRealm))
-	authenticatedUser.PUT("/:id/profile", userController.CreateOrUpdateProfile)
-	authenticatedUser.PUT("/:id/preferences", userController.CreateOrUpdatePreferences)
+	authenticatedUser.Use(middleware.AuthMiddleware(identityProvider))
+
+	// Routes acting on a single user only allow the user themselves or an admin, "me" can be used as the :id
+	ownUser := authenticatedUser.Group("/:id")
+	ownUser.Use(middleware.UserOwnershipMiddleware(adminRole))
+	ownUser.PUT("/profile", userController.CreateOrUpdateProfile)
+	ownUser.PUT("/preferences", userController.CreateOrUpdatePreferences)
 	authenticatedUser.GET("/", userController.FindAll)
+	authenticatedUser.DELETE("/me", accountController.Delete)
+	authenticatedUser.POST("/me/export", dataExportController.Request)
+	authenticatedUser.GET("/me/export/:exportId", dataExportController.Status)
 
 	swipeRouter := v1Router.Group("/swipe")
 	authenticatedSwipe := swipeRouter.Group("/")
-	authenticatedSwipe.Use(middleware.KeycloakAuthMiddleware(keycloak, keycloakClientId, keycloakClientSecret, keycloakRealm))
+	authenticatedSwipe.Use(middleware.AuthMiddleware(identityProvider))
 	authenticatedSwipe.POST("/", swipeController.CreateSwipe)
+	authenticatedSwipe.GET("/received", swipeController.FindLikesReceived)
+
+	adminRouter := v1Router.Group("/admin")
+	adminRouter.Use(middleware.AuthMiddleware(identityProvider))
+	adminRouter.Use(middleware.RequireRole(adminRole))
+	adminRouter.PUT("/users/:id/status", accountController.UpdateStatus)
+	adminRouter.DELETE("/users/:id/deletion", accountController.CancelDeletion)
+
+	// Admins can do everything moderators can
+	moderationRouter := v1Router.Group("/moderation")
+	moderationRouter.Use(middleware.AuthMiddleware(identityProvider))
+	moderationRouter.Use(middleware.RequireRole(moderatorRole, adminRole))
+	moderationRouter.PUT("/users/:id/status", accountController.Moderate)
 
 	return router
 }
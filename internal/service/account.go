@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"github.com/google/uuid"
+)
+
+type AccountService interface {
+	Transition(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error)
+	Moderate(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error)
+	Restore(userID uuid.UUID, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error)
+}
+
+type AccountServiceImpl struct {
+	UserRepository  repository.UserRepository
+	Identity        identity.IdentityProvider
+	PrivilegedRoles []string
+}
+
+// NewAccountService takes the roles whose holders Moderate must leave alone, the admin and moderator roles
+func NewAccountService(userRepo repository.UserRepository, identityProvider identity.IdentityProvider, privilegedRoles []string) AccountService {
+	return &AccountServiceImpl{
+		UserRepository:  userRepo,
+		Identity:        identityProvider,
+		PrivilegedRoles: privilegedRoles,
+	}
+}
+
+// Transition moves the account to the given state if the state machine allows it and records the reason and actor.
+// A nil actorID means the system made the change.
+func (s *AccountServiceImpl) Transition(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountService_Transition")
+	defer span.End()
+
+	return s.transition(userID, to, reason, actorID, model.AccountStatus.CanTransitionTo, childCtx)
+}
+
+// Moderate is Transition limited to what moderators may do, suspending active accounts and reinstating suspended ones.
+// Accounts holding a privileged role are off limits, only an admin can change them through Transition.
+func (s *AccountServiceImpl) Moderate(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountService_Moderate")
+	defer span.End()
+
+	roles, err := s.Identity.Roles(childCtx, userID.String())
+	if errors.Is(err, identity.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to get roles: %s", err)
+		return nil, err
+	}
+	for _, role := range roles {
+		for _, privileged := range s.PrivilegedRoles {
+			if role == privileged {
+				return nil, ErrPrivilegedAccount
+			}
+		}
+	}
+
+	return s.transition(userID, to, reason, actorID, model.AccountStatus.CanModerateTo, childCtx)
+}
+
+// Restore moves a deleted account back to active, only AccountDeletionService.Cancel uses it as the purge must be cancelled too
+func (s *AccountServiceImpl) Restore(userID uuid.UUID, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountService_Restore")
+	defer span.End()
+
+	return s.transition(userID, model.AccountStatusActive, reason, actorID, model.AccountStatus.CanRestoreTo, childCtx)
+}
+
+func (s *AccountServiceImpl) transition(userID uuid.UUID, to model.AccountStatus, reason string, actorID *uuid.UUID, allowed func(from, to model.AccountStatus) bool, childCtx context.Context) (*model.User, error) {
+	user, err := s.UserRepository.FindByID(childCtx, userID.String())
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !allowed(user.Status, to) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	// Identity provider users are created enabled, only touch them when the account starts or stops being usable.
+	// The update runs inside the status transaction, so a failure leaves the local status unchanged too.
+	// Access tokens are checked locally, so locking a user out also ends their sessions.
+	identityUpdated := false
+	applied, err := s.UserRepository.TransitionStatus(childCtx, model.AccountStatusTransition{
+		UserID:     userID,
+		FromStatus: user.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorID:    actorID,
+	}, func() error {
+		if canLogin(user.Status) == canLogin(to) {
+			return nil
+		}
+		if err := s.Identity.SetEnabled(childCtx, userID.String(), canLogin(to)); err != nil {
+			zap.L().Sugar().Errorf("Failed to update identity provider user: %s", err)
+			return err
+		}
+		identityUpdated = true
+		if !canLogin(to) {
+			if err := s.Identity.RevokeSessions(childCtx, userID.String()); err != nil {
+				zap.L().Sugar().Errorf("Failed to revoke sessions: %s", err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to TransitionStatus: %s", err)
+		// The commit failed after the identity provider was updated, put it back
+		if identityUpdated {
+			if err := s.Identity.SetEnabled(childCtx, userID.String(), canLogin(user.Status)); err != nil {
+				zap.L().Sugar().Errorf("Failed to restore identity provider user: %s", err)
+			}
+		}
+		return nil, err
+	}
+	// Someone else changed the status in the meantime
+	if !applied {
+		return nil, ErrInvalidStatusTransition
+	}
+	user.Status = to
+
+	return user, nil
+}
+
+func canLogin(status model.AccountStatus) bool {
+	return status == model.AccountStatusPending || status == model.AccountStatusActive
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+)
+
+// purgeBatchSize bounds how many deletions a single purge run picks up
+const purgeBatchSize = 50
+
+type AccountDeletionService interface {
+	Delete(userID uuid.UUID, ctx context.Context) (*model.AccountDeletion, error)
+	Purge(now time.Time, ctx context.Context) error
+	Cancel(userID uuid.UUID, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error)
+}
+
+type AccountDeletionServiceImpl struct {
+	UserRepository             repository.UserRepository
+	AccountDeletionRepository  repository.AccountDeletionRepository
+	Accounts                   AccountService
+	Identity                   identity.IdentityProvider
+}
+
+func NewAccountDeletionService(userRepo repository.UserRepository, accountDeletionRepo repository.AccountDeletionRepository, accounts AccountService, identityProvider identity.IdentityProvider) AccountDeletionService {
+	return &AccountDeletionServiceImpl{
+		UserRepository:             userRepo,
+		AccountDeletionRepository:  accountDeletionRepo,
+		Accounts:                   accounts,
+		Identity:                   identityProvider,
+	}
+}
+
+// Delete soft-deletes the account right away: it marks the account deleted, which hides it from discovery
+// and disables its login, revokes its sessions and schedules the purge. The purge goes last, so it is never
+// scheduled for an account that is still in use. Each step is skipped when already done, so a failed request can simply be retried.
+// The user can't log in any more, so during the grace period an admin can undo the deletion with Cancel.
+func (s *AccountDeletionServiceImpl) Delete(userID uuid.UUID, ctx context.Context) (*model.AccountDeletion, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountDeletionService_Delete")
+	defer span.End()
+
+	user, err := s.UserRepository.FindByID(childCtx, userID.String())
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.Status != model.AccountStatusDeleted {
+		if _, err := s.Accounts.Transition(userID, model.AccountStatusDeleted, "deleted by user", &userID, childCtx); err != nil {
+			zap.L().Sugar().Errorf("Failed to mark account deleted: %s", err)
+			return nil, err
+		}
+	}
+
+	if err := s.Identity.RevokeSessions(childCtx, userID.String()); err != nil && !errors.Is(err, identity.ErrUserNotFound) {
+		zap.L().Sugar().Errorf("Failed to revoke sessions: %s", err)
+		return nil, err
+	}
+
+	deletion, err := s.AccountDeletionRepository.Schedule(childCtx, userID, time.Now().Add(viper.GetDuration("ACCOUNT_DELETION_GRACE_PERIOD")))
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to schedule account deletion: %s", err)
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+// Cancel restores a deleted account that hasn't been purged yet and drops its scheduled purge.
+// The account is restored first, the purger skips accounts that aren't deleted, so a failed request can simply be retried.
+func (s *AccountDeletionServiceImpl) Cancel(userID uuid.UUID, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountDeletionService_Cancel")
+	defer span.End()
+
+	user, err := s.UserRepository.FindByID(childCtx, userID.String())
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.Status == model.AccountStatusDeleted {
+		user, err = s.Accounts.Restore(userID, reason, actorID, childCtx)
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to restore account: %s", err)
+			return nil, err
+		}
+	}
+
+	if err := s.AccountDeletionRepository.Cancel(childCtx, userID); err != nil {
+		zap.L().Sugar().Errorf("Failed to cancel account deletion: %s", err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Purge hard-deletes the accounts whose grace period is over. A failure is recorded on the deletion
+// and it is retried on the next run, the other accounts are still purged.
+func (s *AccountDeletionServiceImpl) Purge(now time.Time, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "AccountDeletionService_Purge")
+	defer span.End()
+
+	deletions, err := s.AccountDeletionRepository.FindDue(childCtx, now, purgeBatchSize)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindDue: %s", err)
+		return err
+	}
+
+	for _, deletion := range deletions {
+		if err := s.purge(deletion, now, childCtx); err != nil {
+			zap.L().Sugar().Errorf("Failed to purge user %s: %s", deletion.UserID, err)
+			if err := s.AccountDeletionRepository.RecordFailure(childCtx, deletion.ID, err.Error()); err != nil {
+				zap.L().Sugar().Errorf("Failed to RecordFailure: %s", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// purge removes the identity provider user first, the database rows go last in one transaction that also completes
+// the deletion and removes the user's export files
+func (s *AccountDeletionServiceImpl) purge(deletion model.AccountDeletion, now time.Time, ctx context.Context) error {
+	if err := s.Identity.DeleteUser(ctx, deletion.UserID.String()); err != nil && !errors.Is(err, identity.ErrUserNotFound) {
+		return err
+	}
+
+	return s.AccountDeletionRepository.Purge(ctx, deletion, now, func(exports []model.DataExport) error {
+		for _, export := range exports {
+			if err := removeExportFile(export.FilePath); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// StartAccountPurger runs Purge every interval until ctx is done
+func StartAccountPurger(ctx context.Context, accountDeletion AccountDeletionService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if err := accountDeletion.Purge(now, ctx); err != nil {
+					zap.L().Sugar().Warnf("Account purge failed: %s", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
+	mock_service "deals_chatting_app_backend/internal/service/mocks"
+)
+
+// failingDelete can't delete one of its users, like an identity provider that is down for that call
+type failingDelete struct {
+	*identity.Memory
+	userID string
+}
+
+func (f failingDelete) DeleteUser(ctx context.Context, userID string) error {
+	if userID == f.userID {
+		return errors.New("identity provider unavailable")
+	}
+	return f.Memory.DeleteUser(ctx, userID)
+}
+
+// newLoggedInUser creates a user in the identity provider with an open session
+func newLoggedInUser(t *testing.T, identityProvider *identity.Memory) uuid.UUID {
+	id, err := identityProvider.CreateUser(context.Background(), identity.User{Username: uuid.New().String(), Enabled: true})
+	assert.NoError(t, err)
+	identityProvider.SetPassword(context.Background(), id, "password123")
+	user, _ := identityProvider.User(id)
+	_, err = identityProvider.Login(context.Background(), user.Username, "password123")
+	assert.NoError(t, err)
+	return uuid.MustParse(id)
+}
+
+func TestAccountDeletionService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	identityProvider := identity.NewMemory()
+	viper.Set("ACCOUNT_DELETION_GRACE_PERIOD", "720h")
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mockAccounts, identityProvider)
+
+	user := &model.User{ID: newLoggedInUser(t, identityProvider), Status: model.AccountStatusActive}
+	deletion := &model.AccountDeletion{ID: uuid.New(), UserID: user.ID}
+
+	mockUserRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockDeletionRepo.EXPECT().Schedule(gomock.Any(), user.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, userID uuid.UUID, purgeAfter time.Time) (*model.AccountDeletion, error) {
+			assert.WithinDuration(t, time.Now().Add(720*time.Hour), purgeAfter, time.Minute)
+			deletion.PurgeAfter = purgeAfter
+			return deletion, nil
+		})
+	mockAccounts.EXPECT().Transition(user.ID, model.AccountStatusDeleted, "deleted by user", &user.ID, gomock.Any()).Return(user, nil)
+
+	res, err := accountDeletionService.Delete(user.ID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, deletion.ID, res.ID)
+	assert.Equal(t, 0, identityProvider.Sessions(user.ID.String()))
+}
+
+func TestAccountDeletionService_DeleteTransitionFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	identityProvider := identity.NewMemory()
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mockAccounts, identityProvider)
+
+	user := &model.User{ID: newLoggedInUser(t, identityProvider), Status: model.AccountStatusActive}
+
+	// The account is still usable, so no purge is scheduled and the sessions stay
+	mockUserRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockAccounts.EXPECT().Transition(user.ID, model.AccountStatusDeleted, "deleted by user", &user.ID, gomock.Any()).Return(nil, errors.New("db down"))
+
+	_, err := accountDeletionService.Delete(user.ID, context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, identityProvider.Sessions(user.ID.String()))
+}
+
+func TestAccountDeletionService_DeleteRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	identityProvider := identity.NewMemory()
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mockAccounts, identityProvider)
+
+	// A previous attempt got as far as marking the account deleted, only the session revocation is left
+	user := &model.User{ID: newLoggedInUser(t, identityProvider), Status: model.AccountStatusDeleted}
+	deletion := &model.AccountDeletion{ID: uuid.New(), UserID: user.ID}
+
+	mockUserRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockDeletionRepo.EXPECT().Schedule(gomock.Any(), user.ID, gomock.Any()).Return(deletion, nil)
+
+	_, err := accountDeletionService.Delete(user.ID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, identityProvider.Sessions(user.ID.String()))
+}
+
+func TestAccountDeletionService_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mockAccounts, identity.NewMemory())
+
+	adminID := uuid.New()
+	user := &model.User{ID: uuid.New(), Status: model.AccountStatusDeleted}
+	restored := &model.User{ID: user.ID, Status: model.AccountStatusActive}
+
+	mockUserRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	gomock.InOrder(
+		mockAccounts.EXPECT().Restore(user.ID, "asked support", &adminID, gomock.Any()).Return(restored, nil),
+		mockDeletionRepo.EXPECT().Cancel(gomock.Any(), user.ID).Return(nil),
+	)
+
+	res, err := accountDeletionService.Cancel(user.ID, "asked support", &adminID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountStatusActive, res.Status)
+}
+
+func TestAccountDeletionService_CancelRestoreFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mockAccounts, identity.NewMemory())
+
+	adminID := uuid.New()
+	user := &model.User{ID: uuid.New(), Status: model.AccountStatusDeleted}
+
+	// The account stays deleted, so its purge stays scheduled
+	mockUserRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockAccounts.EXPECT().Restore(user.ID, "asked support", &adminID, gomock.Any()).Return(nil, errors.New("db down"))
+
+	_, err := accountDeletionService.Cancel(user.ID, "asked support", &adminID, context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestAccountDeletionService_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	memory := identity.NewMemory()
+	purged := model.AccountDeletion{ID: uuid.New(), UserID: newLoggedInUser(t, memory)}
+	// An earlier run already removed this identity provider user
+	gone := model.AccountDeletion{ID: uuid.New(), UserID: uuid.New()}
+	failing := model.AccountDeletion{ID: uuid.New(), UserID: newLoggedInUser(t, memory)}
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockDeletionRepo := mock_repository.NewMockAccountDeletionRepository(ctrl)
+	identityProvider := failingDelete{Memory: memory, userID: failing.UserID.String()}
+	accountDeletionService := service.NewAccountDeletionService(mockUserRepo, mockDeletionRepo, mock_service.NewMockAccountService(ctrl), identityProvider)
+
+	now := time.Now()
+	mockDeletionRepo.EXPECT().FindDue(gomock.Any(), now, gomock.Any()).Return([]model.AccountDeletion{purged, gone, failing}, nil)
+	// The purged user had an export, the other one's file was already removed
+	exportPath := filepath.Join(t.TempDir(), "export.zip")
+	assert.NoError(t, os.WriteFile(exportPath, []byte("zip"), 0600))
+	mockDeletionRepo.EXPECT().Purge(gomock.Any(), purged, now, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, deletion model.AccountDeletion, now time.Time, removeExports func([]model.DataExport) error) error {
+			return removeExports([]model.DataExport{{UserID: purged.UserID, FilePath: exportPath}})
+		})
+	mockDeletionRepo.EXPECT().Purge(gomock.Any(), gone, now, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, deletion model.AccountDeletion, now time.Time, removeExports func([]model.DataExport) error) error {
+			return removeExports([]model.DataExport{{UserID: gone.UserID, FilePath: filepath.Join(t.TempDir(), "gone.zip")}})
+		})
+	mockDeletionRepo.EXPECT().RecordFailure(gomock.Any(), failing.ID, gomock.Any()).Return(nil)
+
+	assert.NoError(t, accountDeletionService.Purge(now, context.Background()))
+
+	_, exists := memory.User(purged.UserID.String())
+	assert.False(t, exists)
+	_, exists = memory.User(failing.UserID.String())
+	assert.True(t, exists)
+	_, err := os.Stat(exportPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,234 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/google/uuid"
+
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/service"
+	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
+)
+
+var privilegedRoles = []string{"admin", "moderator"}
+
+// applyTransition stands in for the repository transaction, failing it when apply fails
+func applyTransition(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error) {
+	if err := apply(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func TestAccountService_TransitionActivates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	accountService := service.NewAccountService(mockRepo, identity.NewMemory(), privilegedRoles)
+
+	// Pending users are already enabled in the identity provider, so activating them is local only
+	user := &model.User{ID: uuid.New(), Username: "user1", Status: model.AccountStatusPending}
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error) {
+			assert.Equal(t, user.ID, transition.UserID)
+			assert.Equal(t, model.AccountStatusPending, transition.FromStatus)
+			assert.Equal(t, model.AccountStatusActive, transition.ToStatus)
+			assert.Equal(t, "email verified", transition.Reason)
+			assert.Nil(t, transition.ActorID)
+			return applyTransition(ctx, transition, apply)
+		})
+
+	activated, err := accountService.Transition(user.ID, model.AccountStatusActive, "email verified", nil, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountStatusActive, activated.Status)
+}
+
+func TestAccountService_TransitionRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	accountService := service.NewAccountService(mockRepo, identity.NewMemory(), privilegedRoles)
+	actorID := uuid.New()
+
+	// Deleted is final
+	deleted := &model.User{ID: uuid.New(), Status: model.AccountStatusDeleted}
+	mockRepo.EXPECT().FindByID(gomock.Any(), deleted.ID.String()).Return(deleted, nil)
+	_, err := accountService.Transition(deleted.ID, model.AccountStatusActive, "restore", &actorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+	// Pending accounts can't be suspended before they were ever active
+	pending := &model.User{ID: uuid.New(), Status: model.AccountStatusPending}
+	mockRepo.EXPECT().FindByID(gomock.Any(), pending.ID.String()).Return(pending, nil)
+	_, err = accountService.Transition(pending.ID, model.AccountStatusSuspended, "spam", &actorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+	// The status changed between the read and the update
+	raced := &model.User{ID: uuid.New(), Status: model.AccountStatusPending}
+	mockRepo.EXPECT().FindByID(gomock.Any(), raced.ID.String()).Return(raced, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	_, err = accountService.Transition(raced.ID, model.AccountStatusActive, "manual", &actorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+	missing := uuid.New()
+	mockRepo.EXPECT().FindByID(gomock.Any(), missing.String()).Return(nil, nil)
+	_, err = accountService.Transition(missing, model.AccountStatusActive, "manual", &actorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+func TestAccountService_TransitionSuspendEndsSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	accountService := service.NewAccountService(mockRepo, identityProvider, privilegedRoles)
+	actorID := uuid.New()
+
+	user := &model.User{ID: newLoggedInUser(t, identityProvider), Status: model.AccountStatusActive}
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(applyTransition)
+
+	_, err := accountService.Transition(user.ID, model.AccountStatusSuspended, "spam", &actorID, context.Background())
+
+	// Tokens are checked locally, so the open sessions would keep working without this
+	assert.NoError(t, err)
+	assert.Equal(t, 0, identityProvider.Sessions(user.ID.String()))
+	created, _ := identityProvider.User(user.ID.String())
+	assert.False(t, created.Enabled)
+}
+
+func TestAccountService_TransitionIdentityFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	accountService := service.NewAccountService(mockRepo, identity.NewMemory(), privilegedRoles)
+	actorID := uuid.New()
+
+	// The identity provider doesn't know the user, so the status transaction is rolled back
+	user := &model.User{ID: uuid.New(), Status: model.AccountStatusActive}
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(applyTransition)
+
+	_, err := accountService.Transition(user.ID, model.AccountStatusSuspended, "spam", &actorID, context.Background())
+
+	assert.ErrorIs(t, err, identity.ErrUserNotFound)
+}
+
+func TestAccountService_TransitionCommitFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	accountService := service.NewAccountService(mockRepo, identityProvider, privilegedRoles)
+	actorID := uuid.New()
+
+	id, _ := identityProvider.CreateUser(context.Background(), identity.User{Username: "user1", Enabled: true})
+	user := &model.User{ID: uuid.MustParse(id), Status: model.AccountStatusActive}
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, transition model.AccountStatusTransition, apply func() error) (bool, error) {
+			assert.NoError(t, apply())
+			return false, errors.New("commit failed")
+		})
+
+	_, err := accountService.Transition(user.ID, model.AccountStatusSuspended, "spam", &actorID, context.Background())
+
+	// The status wasn't stored, so the user is enabled again in the identity provider
+	assert.Error(t, err)
+	created, _ := identityProvider.User(id)
+	assert.True(t, created.Enabled)
+}
+
+func TestAccountService_Moderate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	accountService := service.NewAccountService(mockRepo, identityProvider, privilegedRoles)
+	moderatorID := uuid.New()
+
+	id, _ := identityProvider.CreateUser(context.Background(), identity.User{Username: "user1", Enabled: false})
+	suspended := &model.User{ID: uuid.MustParse(id), Status: model.AccountStatusSuspended}
+	mockRepo.EXPECT().FindByID(gomock.Any(), suspended.ID.String()).Return(suspended, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(applyTransition)
+
+	reinstated, err := accountService.Moderate(suspended.ID, model.AccountStatusActive, "appeal accepted", &moderatorID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountStatusActive, reinstated.Status)
+	created, _ := identityProvider.User(id)
+	assert.True(t, created.Enabled)
+
+	// Lifting a ban is allowed for admins but not for moderators
+	bannedID, _ := identityProvider.CreateUser(context.Background(), identity.User{Username: "user2", Enabled: false})
+	banned := &model.User{ID: uuid.MustParse(bannedID), Status: model.AccountStatusBanned}
+	mockRepo.EXPECT().FindByID(gomock.Any(), banned.ID.String()).Return(banned, nil)
+	_, err = accountService.Moderate(banned.ID, model.AccountStatusActive, "appeal accepted", &moderatorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+}
+
+func TestAccountService_ModeratePrivileged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	accountService := service.NewAccountService(mockRepo, identityProvider, privilegedRoles)
+	moderatorID := uuid.New()
+
+	// Admins and other moderators can't be suspended by a moderator, the status isn't even looked up
+	for _, role := range privilegedRoles {
+		id, _ := identityProvider.CreateUser(context.Background(), identity.User{Username: role + "1", Enabled: true})
+		assert.NoError(t, identityProvider.AssignRole(context.Background(), id, "user"))
+		assert.NoError(t, identityProvider.AssignRole(context.Background(), id, role))
+
+		_, err := accountService.Moderate(uuid.MustParse(id), model.AccountStatusSuspended, "spam", &moderatorID, context.Background())
+
+		assert.ErrorIs(t, err, service.ErrPrivilegedAccount)
+		user, _ := identityProvider.User(id)
+		assert.True(t, user.Enabled)
+	}
+
+	_, err := accountService.Moderate(uuid.New(), model.AccountStatusSuspended, "spam", &moderatorID, context.Background())
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+func TestAccountService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	accountService := service.NewAccountService(mockRepo, identityProvider, privilegedRoles)
+	adminID := uuid.New()
+
+	id, _ := identityProvider.CreateUser(context.Background(), identity.User{Username: "user1", Enabled: false})
+	deleted := &model.User{ID: uuid.MustParse(id), Status: model.AccountStatusDeleted}
+	mockRepo.EXPECT().FindByID(gomock.Any(), deleted.ID.String()).Return(deleted, nil)
+	mockRepo.EXPECT().TransitionStatus(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(applyTransition)
+
+	restored, err := accountService.Restore(deleted.ID, "asked support", &adminID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountStatusActive, restored.Status)
+	created, _ := identityProvider.User(id)
+	assert.True(t, created.Enabled)
+
+	// Only deleted accounts can be restored
+	banned := &model.User{ID: uuid.New(), Status: model.AccountStatusBanned}
+	mockRepo.EXPECT().FindByID(gomock.Any(), banned.ID.String()).Return(banned, nil)
+	_, err = accountService.Restore(banned.ID, "asked support", &adminID, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+}
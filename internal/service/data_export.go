@@ -0,0 +1,422 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+)
+
+type DataExportService interface {
+	Request(userID uuid.UUID, ctx context.Context) (*model.DataExport, error)
+	Find(userID, exportID uuid.UUID, ctx context.Context) (*model.DataExport, error)
+	Generate(exportID uuid.UUID, ctx context.Context) error
+	DownloadURL(export *model.DataExport) string
+	Open(token string, ctx context.Context) (*model.DataExport, error)
+	PurgeExpired(now time.Time, ctx context.Context) error
+}
+
+type DataExportServiceImpl struct {
+	DataExportRepository  repository.DataExportRepository
+	Jobs                  chan<- uuid.UUID
+}
+
+// NewDataExportService queues generated exports on jobs, see StartDataExportWorker
+func NewDataExportService(dataExportRepo repository.DataExportRepository, jobs chan<- uuid.UUID) DataExportService {
+	return &DataExportServiceImpl{
+		DataExportRepository:  dataExportRepo,
+		Jobs:                  jobs,
+	}
+}
+
+// Request queues a new export for the user, or returns the one still being generated
+func (s *DataExportServiceImpl) Request(userID uuid.UUID, ctx context.Context) (*model.DataExport, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "DataExportService_Request")
+	defer span.End()
+
+	pending, err := s.DataExportRepository.FindPendingByUserID(childCtx, userID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindPendingByUserID: %s", err)
+		return nil, err
+	}
+	if pending != nil {
+		return pending, nil
+	}
+
+	export, err := s.DataExportRepository.Save(childCtx, model.DataExport{UserID: userID, Status: model.DataExportStatusPending})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to save data export: %s", err)
+		return nil, err
+	}
+
+	select {
+	case s.Jobs <- export.ID:
+	default:
+		s.fail(export.ID, "export queue is full", childCtx)
+		return nil, ErrTooManyRequests
+	}
+
+	return export, nil
+}
+
+// Find returns an export of the user, other users' exports are reported as not found
+func (s *DataExportServiceImpl) Find(userID, exportID uuid.UUID, ctx context.Context) (*model.DataExport, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "DataExportService_Find")
+	defer span.End()
+
+	export, err := s.DataExportRepository.FindByID(childCtx, exportID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, ErrDataExportNotFound
+	}
+
+	return export, nil
+}
+
+// Generate collects the user's data into a zip of JSON files under DATA_EXPORT_DIR
+func (s *DataExportServiceImpl) Generate(exportID uuid.UUID, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "DataExportService_Generate")
+	defer span.End()
+
+	export, err := s.DataExportRepository.FindByID(childCtx, exportID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return err
+	}
+	if export == nil || export.Status != model.DataExportStatusPending {
+		return nil
+	}
+
+	userData, err := s.DataExportRepository.CollectUserData(childCtx, export.UserID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to CollectUserData: %s", err)
+		s.fail(exportID, "failed to collect data", childCtx)
+		return err
+	}
+
+	now := time.Now()
+	path := filepath.Join(viper.GetString("DATA_EXPORT_DIR"), exportID.String()+".zip")
+	if err := writeExportFile(path, userData, now); err != nil {
+		zap.L().Sugar().Errorf("Failed to write data export: %s", err)
+		os.Remove(path)
+		s.fail(exportID, "failed to write export", childCtx)
+		return err
+	}
+
+	expiresAt := now.Add(viper.GetDuration("DATA_EXPORT_TTL"))
+	err = s.DataExportRepository.Update(childCtx, exportID, map[string]interface{}{
+		"status":       model.DataExportStatusReady,
+		"file_path":    path,
+		"instance":     viper.GetString("INSTANCE_ID"),
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to update data export: %s", err)
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// DownloadURL returns a link to a ready export that works without logging in until the export expires
+func (s *DataExportServiceImpl) DownloadURL(export *model.DataExport) string {
+	if export.Status != model.DataExportStatusReady || export.ExpiresAt == nil {
+		return ""
+	}
+	token := signToken(viper.GetString("DATA_EXPORT_SECRET"), tokenPurposeDataExport, export.ID, *export.ExpiresAt)
+	return fmt.Sprintf("%s?token=%s", viper.GetString("DATA_EXPORT_DOWNLOAD_URL"), token)
+}
+
+// Open resolves a download token to a ready export
+func (s *DataExportServiceImpl) Open(token string, ctx context.Context) (*model.DataExport, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "DataExportService_Open")
+	defer span.End()
+
+	now := time.Now()
+	exportID, err := parseToken(viper.GetString("DATA_EXPORT_SECRET"), tokenPurposeDataExport, token, now)
+	if err != nil {
+		return nil, ErrInvalidDownloadToken
+	}
+
+	export, err := s.DataExportRepository.FindByID(childCtx, exportID)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if export == nil || export.Status != model.DataExportStatusReady || export.ExpiresAt == nil || now.After(*export.ExpiresAt) {
+		return nil, ErrInvalidDownloadToken
+	}
+	// The zip is on the local disk of the instance that generated it
+	if export.Instance != viper.GetString("INSTANCE_ID") {
+		return nil, ErrDataExportElsewhere
+	}
+
+	return export, nil
+}
+
+// PurgeExpired removes the files of expired exports
+func (s *DataExportServiceImpl) PurgeExpired(now time.Time, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "DataExportService_PurgeExpired")
+	defer span.End()
+
+	exports, err := s.DataExportRepository.FindExpired(childCtx, now)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindExpired: %s", err)
+		return err
+	}
+
+	for _, export := range exports {
+		if err := removeExportFile(export.FilePath); err != nil {
+			zap.L().Sugar().Errorf("Failed to remove data export %s: %s", export.ID, err)
+			continue
+		}
+		err := s.DataExportRepository.Update(childCtx, export.ID, map[string]interface{}{
+			"status":    model.DataExportStatusExpired,
+			"file_path": "",
+		})
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to expire data export %s: %s", export.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *DataExportServiceImpl) fail(exportID uuid.UUID, reason string, ctx context.Context) {
+	err := s.DataExportRepository.Update(ctx, exportID, map[string]interface{}{
+		"status": model.DataExportStatusFailed,
+		"error":  reason,
+	})
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to mark data export failed: %s", err)
+	}
+}
+
+// StartDataExportWorker generates the queued exports one at a time and purges expired ones every purgeInterval.
+// Queued jobs are lost when the process stops, so the exports still pending are generated first on startup.
+// Exports pending for longer than staleAfter, e.g. because storing the result failed, are marked failed with every purge.
+// Exports are kept on the local disk, the worker assumes it is the only instance.
+func StartDataExportWorker(ctx context.Context, dataExportRepo repository.DataExportRepository, dataExport DataExportService, jobs <-chan uuid.UUID, purgeInterval, staleAfter time.Duration) {
+	go func() {
+		pending, err := dataExportRepo.FindPending(ctx)
+		if err != nil {
+			zap.L().Sugar().Warnf("Failed to find pending data exports: %s", err)
+		}
+		for _, export := range pending {
+			if err := dataExport.Generate(export.ID, ctx); err != nil {
+				zap.L().Sugar().Warnf("Data export %s failed: %s", export.ID, err)
+			}
+		}
+
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case exportID := <-jobs:
+				if err := dataExport.Generate(exportID, ctx); err != nil {
+					zap.L().Sugar().Warnf("Data export %s failed: %s", exportID, err)
+				}
+			case now := <-ticker.C:
+				if err := dataExportRepo.FailPending(ctx, "interrupted, please request a new export", now.Add(-staleAfter)); err != nil {
+					zap.L().Sugar().Warnf("Failed to fail interrupted data exports: %s", err)
+				}
+				if err := dataExport.PurgeExpired(now, ctx); err != nil {
+					zap.L().Sugar().Warnf("Data export purge failed: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// removeExportFile deletes a generated export, one that is already gone or was never written is fine
+func removeExportFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func writeExportFile(path string, userData *repository.UserData, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if err := writeExportArchive(archive, userData, now); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeExportArchive writes one JSON file per kind of data, and a manifest describing them
+func writeExportArchive(archive *zip.Writer, userData *repository.UserData, now time.Time) error {
+	user := userData.User
+	manifest := data.ExportManifest{UserID: user.ID.String(), GeneratedAt: now}
+
+	add := func(name, description string, records int, content interface{}) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(content); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, data.ExportManifestFile{Name: name, Description: description, Records: records})
+		return nil
+	}
+
+	err := add("user.json", "Your account", 1, data.UserResponse{
+		ID:         user.ID.String(),
+		Username:   user.Username,
+		Email:      user.Email,
+		IsVerified: user.IsVerified,
+		VerifiedAt: user.VerifiedAt,
+		LastLogin:  user.LastLogin,
+		CreatedAt:  user.CreatedAt,
+		Status:     string(user.Status),
+	})
+	if err != nil {
+		return err
+	}
+
+	if profile := userData.Profile; profile != nil {
+		err := add("profile.json", "Your profile", 1, data.ExportProfile{
+			UserID:    profile.UserID.String(),
+			Fullname:  profile.FullName,
+			DOB:       profile.DOB,
+			Religion:  profile.Religion,
+			Gender:    profile.Gender,
+			Country:   profile.Country,
+			City:      profile.City,
+			Picture:   profile.Picture,
+			CreatedAt: profile.CreatedAt,
+			UpdatedAt: profile.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if preferences := userData.Preferences; preferences != nil {
+		err := add("preferences.json", "Who you want to be matched with", 1, data.Preferences{
+			UserID:    preferences.UserID.String(),
+			MinAge:    preferences.MinAge,
+			MaxAge:    preferences.MaxAge,
+			Religion:  preferences.Religion,
+			Gender:    preferences.Gender,
+			Country:   preferences.Country,
+			City:      preferences.City,
+			CreatedAt: preferences.CreatedAt,
+			UpdatedAt: preferences.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := add("swipes_made.json", "Your likes and passes on other users", len(userData.SwipesMade), toExportSwipes(userData.SwipesMade)); err != nil {
+		return err
+	}
+	if err := add("swipes_received.json", "Other users' likes and passes on you", len(userData.SwipesReceived), toExportSwipes(userData.SwipesReceived)); err != nil {
+		return err
+	}
+	if err := add("status_history.json", "Changes of your account status and their reasons", len(userData.StatusChanges), toExportStatusChanges(user.ID, userData.StatusChanges)); err != nil {
+		return err
+	}
+
+	if deletion := userData.Deletion; deletion != nil {
+		err := add("account_deletion.json", "Your request to delete the account", 1, data.ExportAccountDeletion{
+			RequestedAt: deletion.RequestedAt,
+			PurgeAfter:  deletion.PurgeAfter,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	passwordResets := []data.ExportPasswordReset{}
+	for _, reset := range userData.PasswordResets {
+		passwordResets = append(passwordResets, data.ExportPasswordReset{CreatedAt: reset.CreatedAt, ExpiresAt: reset.ExpiresAt, UsedAt: reset.UsedAt})
+	}
+	if err := add("password_resets.json", "Password reset links sent to you", len(passwordResets), passwordResets); err != nil {
+		return err
+	}
+
+	w, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+// toExportStatusChanges leaves out who of the staff made a change, only that staff did
+func toExportStatusChanges(userID uuid.UUID, transitions []model.AccountStatusTransition) []data.ExportStatusChange {
+	exported := []data.ExportStatusChange{}
+	for _, transition := range transitions {
+		changedBy := "staff"
+		if transition.ActorID == nil {
+			changedBy = "system"
+		} else if *transition.ActorID == userID {
+			changedBy = "you"
+		}
+		exported = append(exported, data.ExportStatusChange{
+			FromStatus: string(transition.FromStatus),
+			ToStatus:   string(transition.ToStatus),
+			Reason:     transition.Reason,
+			ChangedBy:  changedBy,
+			CreatedAt:  transition.CreatedAt,
+		})
+	}
+	return exported
+}
+
+func toExportSwipes(swipes []model.Swipe) []data.Swipe {
+	exported := []data.Swipe{}
+	for _, swipe := range swipes {
+		exported = append(exported, data.Swipe{
+			UserID:          swipe.UserID.String(),
+			SwipedUserID:    swipe.SwipedUserID.String(),
+			IsLiked:         swipe.IsLiked,
+			LikedElement:    swipe.LikedElement,
+			LikedElementRef: swipe.LikedElementRef,
+			Comment:         swipe.Comment,
+			CreatedAt:       swipe.CreatedAt,
+			UpdatedAt:       swipe.UpdatedAt,
+		})
+	}
+	return exported
+}
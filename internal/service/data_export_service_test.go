@@ -0,0 +1,307 @@
+package service_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/repository"
+	"deals_chatting_app_backend/internal/service"
+	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
+	mock_service "deals_chatting_app_backend/internal/service/mocks"
+)
+
+func TestDataExportService_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	jobs := make(chan uuid.UUID, 1)
+	dataExportService := service.NewDataExportService(mockRepo, jobs)
+
+	userID := uuid.New()
+	export := &model.DataExport{ID: uuid.New(), UserID: userID, Status: model.DataExportStatusPending}
+
+	mockRepo.EXPECT().FindPendingByUserID(gomock.Any(), userID).Return(nil, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), model.DataExport{UserID: userID, Status: model.DataExportStatusPending}).Return(export, nil)
+
+	res, err := dataExportService.Request(userID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, export.ID, res.ID)
+	assert.Equal(t, export.ID, <-jobs)
+
+	// Asking again while it is being generated returns the same export
+	mockRepo.EXPECT().FindPendingByUserID(gomock.Any(), userID).Return(export, nil)
+
+	res, err = dataExportService.Request(userID, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, export.ID, res.ID)
+	assert.Len(t, jobs, 0)
+}
+
+func TestDataExportService_RequestQueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	dataExportService := service.NewDataExportService(mockRepo, make(chan uuid.UUID))
+
+	userID := uuid.New()
+	export := &model.DataExport{ID: uuid.New(), UserID: userID, Status: model.DataExportStatusPending}
+
+	mockRepo.EXPECT().FindPendingByUserID(gomock.Any(), userID).Return(nil, nil)
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(export, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), export.ID, gomock.Any()).Return(nil)
+
+	_, err := dataExportService.Request(userID, context.Background())
+
+	assert.ErrorIs(t, err, service.ErrTooManyRequests)
+}
+
+func TestDataExportService_Generate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	dataExportService := service.NewDataExportService(mockRepo, make(chan uuid.UUID, 1))
+	viper.Set("DATA_EXPORT_DIR", t.TempDir())
+	viper.Set("DATA_EXPORT_TTL", "48h")
+
+	user := model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com", Password: "secret", Status: model.AccountStatusActive}
+	otherID := uuid.New()
+	userData := &repository.UserData{
+		User:           user,
+		Profile:        &model.Profile{UserID: user.ID, FullName: "User One", DOB: time.Date(1995, time.May, 4, 0, 0, 0, 0, time.UTC)},
+		SwipesMade:     []model.Swipe{{UserID: user.ID, SwipedUserID: otherID, IsLiked: true, Comment: "hi"}},
+		SwipesReceived: []model.Swipe{},
+		StatusChanges: []model.AccountStatusTransition{
+			{UserID: user.ID, FromStatus: model.AccountStatusPending, ToStatus: model.AccountStatusActive, Reason: "email verified"},
+			{UserID: user.ID, FromStatus: model.AccountStatusActive, ToStatus: model.AccountStatusSuspended, Reason: "spam reports", ActorID: &otherID},
+		},
+		Deletion:       &model.AccountDeletion{UserID: user.ID, PurgeAfter: time.Now().Add(720 * time.Hour)},
+		PasswordResets: []model.PasswordResetToken{{UserID: user.ID, TokenHash: "hash", ExpiresAt: time.Now()}},
+	}
+	export := &model.DataExport{ID: uuid.New(), UserID: user.ID, Status: model.DataExportStatusPending}
+
+	var path string
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(export, nil)
+	mockRepo.EXPECT().CollectUserData(gomock.Any(), user.ID).Return(userData, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), export.ID, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+			assert.Equal(t, model.DataExportStatusReady, fields["status"])
+			assert.WithinDuration(t, time.Now().Add(48*time.Hour), fields["expires_at"].(time.Time), time.Minute)
+			path = fields["file_path"].(string)
+			return nil
+		})
+
+	assert.NoError(t, dataExportService.Generate(export.ID, context.Background()))
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("Failed to open export: %v", err)
+	}
+	defer archive.Close()
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	readJSON := func(name string, v interface{}) {
+		file, exists := files[name]
+		if !exists {
+			t.Fatalf("%s is missing from the export", name)
+		}
+		r, _ := file.Open()
+		defer r.Close()
+		assert.NoError(t, json.NewDecoder(r).Decode(v))
+	}
+
+	manifest := data.ExportManifest{}
+	readJSON("manifest.json", &manifest)
+	assert.Equal(t, user.ID.String(), manifest.UserID)
+	names := []string{}
+	for _, file := range manifest.Files {
+		names = append(names, file.Name)
+	}
+	// No preferences were set, so there is no file for them
+	assert.Equal(t, []string{"user.json", "profile.json", "swipes_made.json", "swipes_received.json", "status_history.json", "account_deletion.json", "password_resets.json"}, names)
+
+	exportedUser := map[string]interface{}{}
+	readJSON("user.json", &exportedUser)
+	assert.Equal(t, "user1@example.com", exportedUser["email"])
+	assert.NotContains(t, exportedUser, "password")
+
+	swipes := []data.Swipe{}
+	readJSON("swipes_made.json", &swipes)
+	assert.Len(t, swipes, 1)
+	assert.Equal(t, otherID.String(), swipes[0].SwipedUserID)
+
+	// Moderation reasons are included, the moderator isn't named
+	statusChanges := []data.ExportStatusChange{}
+	readJSON("status_history.json", &statusChanges)
+	assert.Len(t, statusChanges, 2)
+	assert.Equal(t, "system", statusChanges[0].ChangedBy)
+	assert.Equal(t, "spam reports", statusChanges[1].Reason)
+	assert.Equal(t, "staff", statusChanges[1].ChangedBy)
+
+	passwordResets := []map[string]interface{}{}
+	readJSON("password_resets.json", &passwordResets)
+	assert.Len(t, passwordResets, 1)
+	assert.NotContains(t, passwordResets[0], "token_hash")
+}
+
+func TestDataExportService_FindOtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	dataExportService := service.NewDataExportService(mockRepo, make(chan uuid.UUID, 1))
+
+	export := &model.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: model.DataExportStatusReady}
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(export, nil)
+
+	_, err := dataExportService.Find(uuid.New(), export.ID, context.Background())
+
+	assert.ErrorIs(t, err, service.ErrDataExportNotFound)
+}
+
+func TestDataExportService_DownloadLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	dataExportService := service.NewDataExportService(mockRepo, make(chan uuid.UUID, 1))
+	viper.Set("DATA_EXPORT_SECRET", "test-secret")
+	viper.Set("DATA_EXPORT_DOWNLOAD_URL", "http://localhost/v1/user/export/download")
+
+	expiresAt := time.Now().Add(time.Hour)
+	export := &model.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: model.DataExportStatusReady, ExpiresAt: &expiresAt}
+
+	// Pending exports have no link yet
+	assert.Empty(t, dataExportService.DownloadURL(&model.DataExport{ID: uuid.New(), Status: model.DataExportStatusPending}))
+
+	link, err := url.Parse(dataExportService.DownloadURL(export))
+	assert.NoError(t, err)
+	token := link.Query().Get("token")
+
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(export, nil)
+	opened, err := dataExportService.Open(token, context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, export.ID, opened.ID)
+
+	// The file was purged in the meantime
+	expired := *export
+	expired.Status = model.DataExportStatusExpired
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(&expired, nil)
+	_, err = dataExportService.Open(token, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidDownloadToken)
+
+	_, err = dataExportService.Open(token+"x", context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidDownloadToken)
+
+	// The file is on the disk of another instance
+	elsewhere := *export
+	elsewhere.Instance = "other-host"
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).Return(&elsewhere, nil)
+	_, err = dataExportService.Open(token, context.Background())
+	assert.ErrorIs(t, err, service.ErrDataExportElsewhere)
+}
+
+func TestDataExportService_TokenPurpose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	dataExportService := service.NewDataExportService(mockRepo, make(chan uuid.UUID, 1))
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mock_repository.NewMockUserRepository(ctrl), mock_service.NewMockAccountService(ctrl), mail)
+	// Even with the same secret, tokens can't be used for the other purpose
+	viper.Set("DATA_EXPORT_SECRET", viper.GetString("EMAIL_VERIFICATION_SECRET"))
+	viper.Set("DATA_EXPORT_DOWNLOAD_URL", "http://localhost/v1/user/export/download")
+
+	expiresAt := time.Now().Add(time.Hour)
+	export := &model.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: model.DataExportStatusReady, ExpiresAt: &expiresAt}
+	link, err := url.Parse(dataExportService.DownloadURL(export))
+	assert.NoError(t, err)
+
+	_, err = emailVerificationService.Verify(&data.VerifyEmailRequest{Token: link.Query().Get("token")}, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidVerificationToken)
+
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com"}
+	assert.NoError(t, emailVerificationService.SendVerification(user, context.Background()))
+	_, err = dataExportService.Open(mailedToken(t, mail), context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidDownloadToken)
+}
+
+func TestStartDataExportWorker_RequeuesPendingExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The jobs of a previous run were lost with the process, the exports are generated again
+	export := model.DataExport{ID: uuid.New(), UserID: uuid.New(), Status: model.DataExportStatusPending}
+	generated := make(chan uuid.UUID, 1)
+	mockRepo.EXPECT().FindPending(gomock.Any()).Return([]model.DataExport{export}, nil)
+	mockRepo.EXPECT().FindByID(gomock.Any(), export.ID).DoAndReturn(
+		func(ctx context.Context, id uuid.UUID) (*model.DataExport, error) {
+			generated <- id
+			return nil, nil
+		})
+
+	service.StartDataExportWorker(ctx, mockRepo, service.NewDataExportService(mockRepo, nil), make(chan uuid.UUID), time.Hour, 6*time.Hour)
+
+	select {
+	case id := <-generated:
+		assert.Equal(t, export.ID, id)
+	case <-time.After(time.Second):
+		t.Fatal("Pending export was not generated")
+	}
+}
+
+func TestStartDataExportWorker_FailsOnlyStaleExports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockDataExportRepository(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Exports requested within the last 6 hours may still be waiting in the queue
+	failed := make(chan time.Time, 1)
+	mockRepo.EXPECT().FindPending(gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().FindExpired(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().FailPending(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, reason string, createdBefore time.Time) error {
+			select {
+			case failed <- createdBefore:
+			default:
+			}
+			return nil
+		}).MinTimes(1)
+
+	service.StartDataExportWorker(ctx, mockRepo, service.NewDataExportService(mockRepo, nil), make(chan uuid.UUID), 10*time.Millisecond, 6*time.Hour)
+
+	select {
+	case createdBefore := <-failed:
+		assert.WithinDuration(t, time.Now().Add(-6*time.Hour), createdBefore, time.Minute)
+	case <-time.After(time.Second):
+		t.Fatal("Stale exports were not failed")
+	}
+	cancel()
+}
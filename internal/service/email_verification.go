@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/ratelimit"
+	"deals_chatting_app_backend/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"github.com/spf13/viper"
+)
+
+type EmailVerificationService interface {
+	SendVerification(*model.User, context.Context) error
+	Verify(*data.VerifyEmailRequest, context.Context) (*model.User, error)
+	Resend(*data.ResendVerificationRequest, string, context.Context) error
+}
+
+type EmailVerificationServiceImpl struct {
+	UserRepository  repository.UserRepository
+	Identity        identity.IdentityProvider
+	Mailer          mailer.Mailer
+	EmailLimiter    ratelimit.Limiter
+	IPLimiter       ratelimit.Limiter
+	Accounts        AccountService
+}
+
+func NewEmailVerificationService(userRepo repository.UserRepository, identityProvider identity.IdentityProvider, mailer mailer.Mailer, emailLimiter, ipLimiter ratelimit.Limiter, accounts AccountService) EmailVerificationService {
+	return &EmailVerificationServiceImpl{
+		UserRepository:  userRepo,
+		Identity:        identityProvider,
+		Mailer:          mailer,
+		EmailLimiter:    emailLimiter,
+		IPLimiter:       ipLimiter,
+		Accounts:        accounts,
+	}
+}
+
+// SendVerification mails the user a signed link that proves they own the address
+func (s *EmailVerificationServiceImpl) SendVerification(user *model.User, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "EmailVerificationService_SendVerification")
+	defer span.End()
+
+	ttl := viper.GetDuration("EMAIL_VERIFICATION_TOKEN_TTL")
+	token := signToken(viper.GetString("EMAIL_VERIFICATION_SECRET"), tokenPurposeVerifyEmail, user.ID, time.Now().Add(ttl))
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address with the link below. It expires in %s.\n\n%s?token=%s",
+			user.Username, ttl, viper.GetString("EMAIL_VERIFICATION_URL"), token),
+	}
+	if err := s.Mailer.Send(childCtx, msg); err != nil {
+		zap.L().Sugar().Errorf("Failed to send verification email: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+// Verify marks the user of a valid token as verified, both locally and in the identity provider, and activates a pending account
+func (s *EmailVerificationServiceImpl) Verify(req *data.VerifyEmailRequest, ctx context.Context) (*model.User, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "EmailVerificationService_Verify")
+	defer span.End()
+
+	userID, err := parseToken(viper.GetString("EMAIL_VERIFICATION_SECRET"), tokenPurposeVerifyEmail, req.Token, time.Now())
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.UserRepository.FindByID(childCtx, userID.String())
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByID: %s", err)
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.IsVerified {
+		return s.activate(user, childCtx)
+	}
+
+	if err := s.Identity.SetEmailVerified(childCtx, userID.String()); err != nil {
+		zap.L().Sugar().Errorf("Failed to mark email verified in identity provider: %s", err)
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+	if err := s.UserRepository.MarkVerified(childCtx, userID, verifiedAt); err != nil {
+		zap.L().Sugar().Errorf("Failed to MarkVerified: %s", err)
+		return nil, err
+	}
+	user.IsVerified = true
+	user.VerifiedAt = verifiedAt
+
+	return s.activate(user, childCtx)
+}
+
+// activate moves a pending account to active, verifying again retries it if it failed
+func (s *EmailVerificationServiceImpl) activate(user *model.User, ctx context.Context) (*model.User, error) {
+	if user.Status != model.AccountStatusPending {
+		return user, nil
+	}
+
+	activated, err := s.Accounts.Transition(user.ID, model.AccountStatusActive, "email verified", nil, ctx)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to activate account: %s", err)
+		return nil, err
+	}
+
+	return activated, nil
+}
+
+// Resend sends a fresh link to an unverified account, without revealing whether the email is registered
+func (s *EmailVerificationServiceImpl) Resend(req *data.ResendVerificationRequest, clientIP string, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "EmailVerificationService_Resend")
+	defer span.End()
+
+	if !s.IPLimiter.Allow(clientIP) || !s.EmailLimiter.Allow(strings.ToLower(req.Email)) {
+		return ErrTooManyRequests
+	}
+
+	user, err := s.UserRepository.FindByEmail(childCtx, req.Email)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByEmail: %s", err)
+		return err
+	}
+	if user == nil || user.IsVerified {
+		return nil
+	}
+
+	// Mailed in the background so the response takes as long whether or not there is anything to send
+	go func(ctx context.Context) {
+		if err := s.SendVerification(user, ctx); err != nil {
+			zap.L().Sugar().Errorf("Failed to resend verification: %s", err)
+		}
+	}(context.WithoutCancel(childCtx))
+
+	return nil
+}
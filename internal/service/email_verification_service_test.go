@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/ratelimit"
+	"deals_chatting_app_backend/internal/service"
+	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
+	mock_service "deals_chatting_app_backend/internal/service/mocks"
+)
+
+var verificationTokenPattern = regexp.MustCompile(`\?token=(\S+)`)
+
+func newEmailVerificationService(userRepo *mock_repository.MockUserRepository, accounts *mock_service.MockAccountService, mail mailer.Mailer) service.EmailVerificationService {
+	viper.Set("EMAIL_VERIFICATION_SECRET", "test-secret")
+	viper.Set("EMAIL_VERIFICATION_TOKEN_TTL", "24h")
+	return service.NewEmailVerificationService(userRepo, identity.NewMemory(), mail, ratelimit.NewMemoryLimiter(2, time.Hour), ratelimit.NewMemoryLimiter(5, time.Hour), accounts)
+}
+
+// mailedToken pulls the token out of the last captured verification mail
+func mailedToken(t *testing.T, mail *mailer.CaptureMailer) string {
+	messages := mail.Messages()
+	if len(messages) == 0 {
+		t.Fatalf("No mail was sent")
+	}
+	match := verificationTokenPattern.FindStringSubmatch(messages[len(messages)-1].Body)
+	if match == nil {
+		t.Fatalf("No token in mail: %s", messages[len(messages)-1].Body)
+	}
+	return match[1]
+}
+
+func TestEmailVerificationService_VerifyAlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mockRepo, mock_service.NewMockAccountService(ctrl), mail)
+
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com", IsVerified: true, Status: model.AccountStatusActive}
+	assert.NoError(t, emailVerificationService.SendVerification(user, context.Background()))
+
+	// No identity provider call or update is needed for a user that is already verified
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+
+	verified, err := emailVerificationService.Verify(&data.VerifyEmailRequest{Token: mailedToken(t, mail)}, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, verified.ID)
+}
+
+func TestEmailVerificationService_VerifyActivatesPendingAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockAccounts := mock_service.NewMockAccountService(ctrl)
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mockRepo, mockAccounts, mail)
+
+	// Verified before activation existed, verifying again activates the account
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com", IsVerified: true, Status: model.AccountStatusPending}
+	assert.NoError(t, emailVerificationService.SendVerification(user, context.Background()))
+
+	activated := *user
+	activated.Status = model.AccountStatusActive
+	mockRepo.EXPECT().FindByID(gomock.Any(), user.ID.String()).Return(user, nil)
+	mockAccounts.EXPECT().Transition(user.ID, model.AccountStatusActive, "email verified", nil, gomock.Any()).Return(&activated, nil)
+
+	verified, err := emailVerificationService.Verify(&data.VerifyEmailRequest{Token: mailedToken(t, mail)}, context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountStatusActive, verified.Status)
+}
+
+func TestEmailVerificationService_VerifyTamperedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mockRepo, mock_service.NewMockAccountService(ctrl), mail)
+
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com"}
+	assert.NoError(t, emailVerificationService.SendVerification(user, context.Background()))
+
+	// Signed with another secret
+	token := mailedToken(t, mail)
+	viper.Set("EMAIL_VERIFICATION_SECRET", "other-secret")
+
+	_, err := emailVerificationService.Verify(&data.VerifyEmailRequest{Token: token}, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidVerificationToken)
+
+	_, err = emailVerificationService.Verify(&data.VerifyEmailRequest{Token: "garbage"}, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidVerificationToken)
+}
+
+func TestEmailVerificationService_VerifyExpiredToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mockRepo, mock_service.NewMockAccountService(ctrl), mail)
+	viper.Set("EMAIL_VERIFICATION_TOKEN_TTL", "-1m")
+
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com"}
+	assert.NoError(t, emailVerificationService.SendVerification(user, context.Background()))
+
+	_, err := emailVerificationService.Verify(&data.VerifyEmailRequest{Token: mailedToken(t, mail)}, context.Background())
+	assert.ErrorIs(t, err, service.ErrInvalidVerificationToken)
+}
+
+func TestEmailVerificationService_Resend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	emailVerificationService := newEmailVerificationService(mockRepo, mock_service.NewMockAccountService(ctrl), mail)
+
+	unverified := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com"}
+	verified := &model.User{ID: uuid.New(), Username: "user2", Email: "user2@example.com", IsVerified: true}
+
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), unverified.Email).Return(unverified, nil)
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), verified.Email).Return(verified, nil)
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, nil)
+
+	assert.NoError(t, emailVerificationService.Resend(&data.ResendVerificationRequest{Email: unverified.Email}, "10.0.0.1", context.Background()))
+	assert.NoError(t, emailVerificationService.Resend(&data.ResendVerificationRequest{Email: verified.Email}, "10.0.0.1", context.Background()))
+	assert.NoError(t, emailVerificationService.Resend(&data.ResendVerificationRequest{Email: "nobody@example.com"}, "10.0.0.1", context.Background()))
+
+	// Only the unverified account gets a mail, sent in the background
+	assert.Eventually(t, func() bool { return len(mail.Messages()) > 0 }, time.Second, 10*time.Millisecond)
+	messages := mail.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, unverified.Email, messages[0].To)
+}
+
+func TestEmailVerificationService_ResendRateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	emailVerificationService := newEmailVerificationService(mockRepo, mock_service.NewMockAccountService(ctrl), mailer.NewCaptureMailer())
+
+	mockRepo.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, nil).Times(5)
+
+	// The per IP limit applies across emails
+	for i := 0; i < 5; i++ {
+		req := &data.ResendVerificationRequest{Email: uuid.New().String() + "@example.com"}
+		assert.NoError(t, emailVerificationService.Resend(req, "10.0.0.1", context.Background()))
+	}
+	req := &data.ResendVerificationRequest{Email: "user1@example.com"}
+	assert.ErrorIs(t, emailVerificationService.Resend(req, "10.0.0.1", context.Background()), service.ErrTooManyRequests)
+}
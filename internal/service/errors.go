@@ -0,0 +1,15 @@
+package service
+
+import "errors"
+
+var ErrTooManyRequests = errors.New("too many requests, please try again later")
+var ErrInvalidResetToken = errors.New("reset token is invalid or has expired")
+var ErrInvalidVerificationToken = errors.New("verification token is invalid or has expired")
+var ErrUserNotFound = errors.New("user not found")
+var ErrInvalidStatusTransition = errors.New("account status transition is not allowed")
+var ErrAccountNotActive = errors.New("account is not active")
+var ErrPrivilegedAccount = errors.New("admin and moderator accounts can't be moderated")
+var ErrDataExportNotFound = errors.New("data export not found")
+var ErrInvalidLikedElement = errors.New("liked element is not part of the profile")
+var ErrInvalidDownloadToken = errors.New("download link is invalid or has expired")
+var ErrDataExportElsewhere = errors.New("data export is stored on another instance")
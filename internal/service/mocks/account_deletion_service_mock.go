@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/account_deletion.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "deals_chatting_app_backend/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAccountDeletionService is a mock of AccountDeletionService interface.
+type MockAccountDeletionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeletionServiceMockRecorder
+}
+
+// MockAccountDeletionServiceMockRecorder is the mock recorder for MockAccountDeletionService.
+type MockAccountDeletionServiceMockRecorder struct {
+	mock *MockAccountDeletionService
+}
+
+// NewMockAccountDeletionService creates a new mock instance.
+func NewMockAccountDeletionService(ctrl *gomock.Controller) *MockAccountDeletionService {
+	mock := &MockAccountDeletionService{ctrl: ctrl}
+	mock.recorder = &MockAccountDeletionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeletionService) EXPECT() *MockAccountDeletionServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockAccountDeletionService) Cancel(userID uuid.UUID, reason string, actorID *uuid.UUID, ctx context.Context) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", userID, reason, actorID, ctx)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockAccountDeletionServiceMockRecorder) Cancel(userID, reason, actorID, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockAccountDeletionService)(nil).Cancel), userID, reason, actorID, ctx)
+}
+
+// Delete mocks base method.
+func (m *MockAccountDeletionService) Delete(userID uuid.UUID, ctx context.Context) (*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userID, ctx)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccountDeletionServiceMockRecorder) Delete(userID, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccountDeletionService)(nil).Delete), userID, ctx)
+}
+
+// Purge mocks base method.
+func (m *MockAccountDeletionService) Purge(now time.Time, ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", now, ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockAccountDeletionServiceMockRecorder) Purge(now, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockAccountDeletionService)(nil).Purge), now, ctx)
+}
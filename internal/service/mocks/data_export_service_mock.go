@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/data_export.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "deals_chatting_app_backend/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDataExportService is a mock of DataExportService interface.
+type MockDataExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportServiceMockRecorder
+}
+
+// MockDataExportServiceMockRecorder is the mock recorder for MockDataExportService.
+type MockDataExportServiceMockRecorder struct {
+	mock *MockDataExportService
+}
+
+// NewMockDataExportService creates a new mock instance.
+func NewMockDataExportService(ctrl *gomock.Controller) *MockDataExportService {
+	mock := &MockDataExportService{ctrl: ctrl}
+	mock.recorder = &MockDataExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportService) EXPECT() *MockDataExportServiceMockRecorder {
+	return m.recorder
+}
+
+// DownloadURL mocks base method.
+func (m *MockDataExportService) DownloadURL(export *model.DataExport) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadURL", export)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DownloadURL indicates an expected call of DownloadURL.
+func (mr *MockDataExportServiceMockRecorder) DownloadURL(export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURL", reflect.TypeOf((*MockDataExportService)(nil).DownloadURL), export)
+}
+
+// Find mocks base method.
+func (m *MockDataExportService) Find(userID, exportID uuid.UUID, ctx context.Context) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", userID, exportID, ctx)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockDataExportServiceMockRecorder) Find(userID, exportID, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDataExportService)(nil).Find), userID, exportID, ctx)
+}
+
+// Generate mocks base method.
+func (m *MockDataExportService) Generate(exportID uuid.UUID, ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", exportID, ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockDataExportServiceMockRecorder) Generate(exportID, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockDataExportService)(nil).Generate), exportID, ctx)
+}
+
+// Open mocks base method.
+func (m *MockDataExportService) Open(token string, ctx context.Context) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", token, ctx)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockDataExportServiceMockRecorder) Open(token, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockDataExportService)(nil).Open), token, ctx)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockDataExportService) PurgeExpired(now time.Time, ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", now, ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockDataExportServiceMockRecorder) PurgeExpired(now, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockDataExportService)(nil).PurgeExpired), now, ctx)
+}
+
+// Request mocks base method.
+func (m *MockDataExportService) Request(userID uuid.UUID, ctx context.Context) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", userID, ctx)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockDataExportServiceMockRecorder) Request(userID, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockDataExportService)(nil).Request), userID, ctx)
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/email_verification.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	data "deals_chatting_app_backend/internal/data"
+	model "deals_chatting_app_backend/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEmailVerificationService is a mock of EmailVerificationService interface.
+type MockEmailVerificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationServiceMockRecorder
+}
+
+// MockEmailVerificationServiceMockRecorder is the mock recorder for MockEmailVerificationService.
+type MockEmailVerificationServiceMockRecorder struct {
+	mock *MockEmailVerificationService
+}
+
+// NewMockEmailVerificationService creates a new mock instance.
+func NewMockEmailVerificationService(ctrl *gomock.Controller) *MockEmailVerificationService {
+	mock := &MockEmailVerificationService{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerificationService) EXPECT() *MockEmailVerificationServiceMockRecorder {
+	return m.recorder
+}
+
+// Resend mocks base method.
+func (m *MockEmailVerificationService) Resend(arg0 *data.ResendVerificationRequest, arg1 string, arg2 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resend", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resend indicates an expected call of Resend.
+func (mr *MockEmailVerificationServiceMockRecorder) Resend(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resend", reflect.TypeOf((*MockEmailVerificationService)(nil).Resend), arg0, arg1, arg2)
+}
+
+// SendVerification mocks base method.
+func (m *MockEmailVerificationService) SendVerification(arg0 *model.User, arg1 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockEmailVerificationServiceMockRecorder) SendVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockEmailVerificationService)(nil).SendVerification), arg0, arg1)
+}
+
+// Verify mocks base method.
+func (m *MockEmailVerificationService) Verify(arg0 *data.VerifyEmailRequest, arg1 context.Context) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockEmailVerificationServiceMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockEmailVerificationService)(nil).Verify), arg0, arg1)
+}
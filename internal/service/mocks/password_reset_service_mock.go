@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/password_reset.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	data "deals_chatting_app_backend/internal/data"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordResetService is a mock of PasswordResetService interface.
+type MockPasswordResetService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetServiceMockRecorder
+}
+
+// MockPasswordResetServiceMockRecorder is the mock recorder for MockPasswordResetService.
+type MockPasswordResetServiceMockRecorder struct {
+	mock *MockPasswordResetService
+}
+
+// NewMockPasswordResetService creates a new mock instance.
+func NewMockPasswordResetService(ctrl *gomock.Controller) *MockPasswordResetService {
+	mock := &MockPasswordResetService{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetService) EXPECT() *MockPasswordResetServiceMockRecorder {
+	return m.recorder
+}
+
+// Forgot mocks base method.
+func (m *MockPasswordResetService) Forgot(arg0 *data.ForgotPasswordRequest, arg1 string, arg2 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forgot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Forgot indicates an expected call of Forgot.
+func (mr *MockPasswordResetServiceMockRecorder) Forgot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forgot", reflect.TypeOf((*MockPasswordResetService)(nil).Forgot), arg0, arg1, arg2)
+}
+
+// Reset mocks base method.
+func (m *MockPasswordResetService) Reset(arg0 *data.ResetPasswordRequest, arg1 string, arg2 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockPasswordResetServiceMockRecorder) Reset(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockPasswordResetService)(nil).Reset), arg0, arg1, arg2)
+}
@@ -51,3 +51,18 @@ func (mr *MockSwipeServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSwipeService)(nil).Create), arg0, arg1, arg2)
 }
+
+// FindLikesReceived mocks base method.
+func (m *MockSwipeService) FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLikesReceived", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]model.Swipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLikesReceived indicates an expected call of FindLikesReceived.
+func (mr *MockSwipeServiceMockRecorder) FindLikesReceived(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLikesReceived", reflect.TypeOf((*MockSwipeService)(nil).FindLikesReceived), ctx, userID, limit, offset)
+}
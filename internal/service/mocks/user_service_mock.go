@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	data "deals_chatting_app_backend/internal/data"
+	identity "deals_chatting_app_backend/internal/identity"
 	model "deals_chatting_app_backend/internal/model"
 	reflect "reflect"
 
@@ -113,10 +114,10 @@ func (mr *MockUserServiceMockRecorder) GetProfileByUserID(ctx, userID interface{
 }
 
 // Login mocks base method.
-func (m *MockUserService) Login(arg0 *data.UserLoginRequest, arg1 context.Context) (*string, error) {
+func (m *MockUserService) Login(arg0 *data.UserLoginRequest, arg1 context.Context) (*identity.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", arg0, arg1)
-	ret0, _ := ret[0].(*string)
+	ret0, _ := ret[0].(*identity.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -126,3 +127,32 @@ func (mr *MockUserServiceMockRecorder) Login(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), arg0, arg1)
 }
+
+// Logout mocks base method.
+func (m *MockUserService) Logout(arg0 *data.LogoutRequest, arg1 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserServiceMockRecorder) Logout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserService)(nil).Logout), arg0, arg1)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserService) RefreshToken(arg0 *data.RefreshTokenRequest, arg1 context.Context) (*identity.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(*identity.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserServiceMockRecorder) RefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserService)(nil).RefreshToken), arg0, arg1)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/ratelimit"
+	"deals_chatting_app_backend/internal/repository"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"github.com/spf13/viper"
+)
+
+type PasswordResetService interface {
+	Forgot(*data.ForgotPasswordRequest, string, context.Context) error
+	Reset(*data.ResetPasswordRequest, string, context.Context) error
+}
+
+type PasswordResetServiceImpl struct {
+	UserRepository          repository.UserRepository
+	PasswordResetRepository repository.PasswordResetRepository
+	Identity                identity.IdentityProvider
+	Mailer                  mailer.Mailer
+	EmailLimiter            ratelimit.Limiter
+	IPLimiter               ratelimit.Limiter
+}
+
+func NewPasswordResetService(userRepo repository.UserRepository, passwordResetRepo repository.PasswordResetRepository, identityProvider identity.IdentityProvider, mailer mailer.Mailer, emailLimiter, ipLimiter ratelimit.Limiter) PasswordResetService {
+	return &PasswordResetServiceImpl{
+		UserRepository:          userRepo,
+		PasswordResetRepository: passwordResetRepo,
+		Identity:                identityProvider,
+		Mailer:                  mailer,
+		EmailLimiter:            emailLimiter,
+		IPLimiter:               ipLimiter,
+	}
+}
+
+// Forgot mails a reset link when the email belongs to an account.
+// It returns nil whether or not the account exists, so callers can't probe for accounts.
+func (s *PasswordResetServiceImpl) Forgot(req *data.ForgotPasswordRequest, clientIP string, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "PasswordResetService_Forgot")
+	defer span.End()
+
+	if !s.IPLimiter.Allow(clientIP) || !s.EmailLimiter.Allow(strings.ToLower(req.Email)) {
+		return ErrTooManyRequests
+	}
+
+	user, err := s.UserRepository.FindByEmail(childCtx, req.Email)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindByEmail: %s", err)
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	// The token is stored and mailed in the background so the response takes as long whether or not the account exists
+	go s.sendResetLink(user, context.WithoutCancel(childCtx))
+
+	return nil
+}
+
+// sendResetLink stores a new reset token for the user and mails it. Failures are only logged, reporting them
+// would reveal the account exists.
+func (s *PasswordResetServiceImpl) sendResetLink(user *model.User, ctx context.Context) {
+	token, tokenHash, err := newResetToken()
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to generate reset token: %s", err)
+		return
+	}
+
+	resetToken := model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(viper.GetDuration("PASSWORD_RESET_TOKEN_TTL")),
+	}
+	if _, err := s.PasswordResetRepository.Save(ctx, resetToken); err != nil {
+		zap.L().Sugar().Errorf("Failed to save reset token: %s", err)
+		return
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s?token=%s\n\nIf you didn't ask for this, you can ignore this email.",
+			user.Username, viper.GetDuration("PASSWORD_RESET_TOKEN_TTL"), viper.GetString("PASSWORD_RESET_URL"), token),
+	}
+	if err := s.Mailer.Send(ctx, msg); err != nil {
+		zap.L().Sugar().Errorf("Failed to send reset email: %s", err)
+	}
+}
+
+func (s *PasswordResetServiceImpl) Reset(req *data.ResetPasswordRequest, clientIP string, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "PasswordResetService_Reset")
+	defer span.End()
+
+	if !s.IPLimiter.Allow(clientIP) {
+		return ErrTooManyRequests
+	}
+
+	now := time.Now()
+	resetToken, err := s.PasswordResetRepository.Consume(childCtx, hashResetToken(req.Token), now)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to consume reset token: %s", err)
+		return err
+	}
+	if resetToken == nil {
+		return ErrInvalidResetToken
+	}
+
+	err = s.Identity.SetPassword(childCtx, resetToken.UserID.String(), req.Password)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to set user password: %s", err)
+		return err
+	}
+
+	// Any other link that was mailed out is useless now
+	if err := s.PasswordResetRepository.InvalidateForUser(childCtx, resetToken.UserID, now); err != nil {
+		zap.L().Sugar().Errorf("Failed to invalidate reset tokens: %s", err)
+	}
+
+	// Whoever knew the old password may still be logged in, end their sessions
+	if err := s.Identity.RevokeSessions(childCtx, resetToken.UserID.String()); err != nil {
+		zap.L().Sugar().Errorf("Failed to revoke sessions: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+// newResetToken returns a random token for the email and the hash to store
+func newResetToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashResetToken(token), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
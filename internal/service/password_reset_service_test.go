@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/spf13/viper"
+	"github.com/google/uuid"
+
+	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/model"
+	"deals_chatting_app_backend/internal/ratelimit"
+	"deals_chatting_app_backend/internal/service"
+	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
+)
+
+func newPasswordResetService(userRepo *mock_repository.MockUserRepository, resetRepo *mock_repository.MockPasswordResetRepository, mail mailer.Mailer) service.PasswordResetService {
+	viper.Set("PASSWORD_RESET_TOKEN_TTL", "30m")
+	return service.NewPasswordResetService(userRepo, resetRepo, identity.NewMemory(), mail, ratelimit.NewMemoryLimiter(2, time.Hour), ratelimit.NewMemoryLimiter(5, time.Hour))
+}
+
+func TestPasswordResetService_Forgot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockResetRepo := mock_repository.NewMockPasswordResetRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	passwordResetService := newPasswordResetService(mockUserRepo, mockResetRepo, mail)
+
+	user := &model.User{ID: uuid.New(), Username: "user1", Email: "user1@example.com"}
+	var savedToken model.PasswordResetToken
+
+	mockUserRepo.EXPECT().FindByEmail(gomock.Any(), user.Email).Return(user, nil)
+	mockResetRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token model.PasswordResetToken) (*model.PasswordResetToken, error) {
+		savedToken = token
+		return &token, nil
+	})
+
+	err := passwordResetService.Forgot(&data.ForgotPasswordRequest{Email: user.Email}, "10.0.0.1", context.Background())
+
+	assert.NoError(t, err)
+	// The link is stored and mailed in the background
+	assert.Eventually(t, func() bool { return len(mail.Messages()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, user.ID, savedToken.UserID)
+	assert.True(t, savedToken.ExpiresAt.After(time.Now()))
+
+	// Only the hash is stored, the mail carries the token itself
+	messages := mail.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, user.Email, messages[0].To)
+	assert.NotContains(t, messages[0].Body, savedToken.TokenHash)
+	assert.True(t, strings.Contains(messages[0].Body, "?token="))
+}
+
+func TestPasswordResetService_ForgotUnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockResetRepo := mock_repository.NewMockPasswordResetRepository(ctrl)
+	mail := mailer.NewCaptureMailer()
+	passwordResetService := newPasswordResetService(mockUserRepo, mockResetRepo, mail)
+
+	mockUserRepo.EXPECT().FindByEmail(gomock.Any(), "nobody@example.com").Return(nil, nil)
+
+	err := passwordResetService.Forgot(&data.ForgotPasswordRequest{Email: "nobody@example.com"}, "10.0.0.1", context.Background())
+
+	assert.NoError(t, err)
+	assert.Empty(t, mail.Messages())
+}
+
+func TestPasswordResetService_ForgotRateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockResetRepo := mock_repository.NewMockPasswordResetRepository(ctrl)
+	passwordResetService := newPasswordResetService(mockUserRepo, mockResetRepo, mailer.NewCaptureMailer())
+
+	mockUserRepo.EXPECT().FindByEmail(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	req := &data.ForgotPasswordRequest{Email: "nobody@example.com"}
+	assert.NoError(t, passwordResetService.Forgot(req, "10.0.0.1", context.Background()))
+	assert.NoError(t, passwordResetService.Forgot(req, "10.0.0.2", context.Background()))
+
+	// The per email limit applies across IPs and is case insensitive
+	req = &data.ForgotPasswordRequest{Email: "Nobody@example.com"}
+	assert.ErrorIs(t, passwordResetService.Forgot(req, "10.0.0.3", context.Background()), service.ErrTooManyRequests)
+}
+
+func TestPasswordResetService_Reset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResetRepo := mock_repository.NewMockPasswordResetRepository(ctrl)
+	identityProvider := identity.NewMemory()
+	passwordResetService := service.NewPasswordResetService(mock_repository.NewMockUserRepository(ctrl), mockResetRepo, identityProvider, mailer.NewCaptureMailer(), ratelimit.NewMemoryLimiter(2, time.Hour), ratelimit.NewMemoryLimiter(5, time.Hour))
+
+	// The user is still logged in somewhere, maybe by whoever took the account over
+	userID := newLoggedInUser(t, identityProvider)
+	resetToken := &model.PasswordResetToken{ID: uuid.New(), UserID: userID}
+	mockResetRepo.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any()).Return(resetToken, nil)
+	mockResetRepo.EXPECT().InvalidateForUser(gomock.Any(), userID, gomock.Any()).Return(nil)
+
+	err := passwordResetService.Reset(&data.ResetPasswordRequest{Token: "valid", Password: "newpassword"}, "10.0.0.1", context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, identityProvider.Sessions(userID.String()))
+	user, _ := identityProvider.User(userID.String())
+	_, err = identityProvider.Login(context.Background(), user.Username, "password123")
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+	_, err = identityProvider.Login(context.Background(), user.Username, "newpassword")
+	assert.NoError(t, err)
+}
+
+func TestPasswordResetService_ResetInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockResetRepo := mock_repository.NewMockPasswordResetRepository(ctrl)
+	passwordResetService := newPasswordResetService(mockUserRepo, mockResetRepo, mailer.NewCaptureMailer())
+
+	mockResetRepo.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	err := passwordResetService.Reset(&data.ResetPasswordRequest{Token: "used-or-expired", Password: "newpassword"}, "10.0.0.1", context.Background())
+
+	assert.ErrorIs(t, err, service.ErrInvalidResetToken)
+}
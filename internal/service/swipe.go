@@ -2,33 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"deals_chatting_app_backend/internal/data"
 	"deals_chatting_app_backend/internal/model"
 	"deals_chatting_app_backend/internal/repository"
 
 	"go.uber.org/zap"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type SwipeService interface {
 	Create(*data.CreateSwipeRequest, uuid.UUID, context.Context) (*model.Swipe, error)
+	FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error)
 }
 
 type SwipeServiceImpl struct {
 	SwipeRepository  repository.SwipeRepository
+	UserRepository   repository.UserRepository
 }
 
-func NewSwipeService(swipeRepo repository.SwipeRepository,) SwipeService {
+func NewSwipeService(swipeRepo repository.SwipeRepository, userRepo repository.UserRepository) SwipeService {
 	return &SwipeServiceImpl{
 		SwipeRepository:  swipeRepo,
+		UserRepository:   userRepo,
 	}
 }
 
 func (s *SwipeServiceImpl) Create(req *data.CreateSwipeRequest, userID uuid.UUID, ctx context.Context) (*model.Swipe, error) {
+	swipedUserID := uuid.MustParse(req.SwipedUserID)
+
+	// A like can only point at something the recipient actually has on their profile
+	if req.LikedElement != "" {
+		profile, err := s.UserRepository.GetProfileByUserID(ctx, swipedUserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidLikedElement
+		}
+		if err != nil {
+			zap.L().Sugar().Errorf("Failed to GetProfileByUserID: %s", err)
+			return nil, err
+		}
+		if !profile.HasElement(req.LikedElement, req.LikedElementRef) {
+			return nil, ErrInvalidLikedElement
+		}
+	}
+
 	// Map ProfileUpdateRequest to model.Swipe
 	swipe := model.Swipe{
-		SwipedUserID:	uuid.MustParse(req.SwipedUserID),
+		SwipedUserID:	swipedUserID,
 		IsLiked:		req.IsLiked,
+		LikedElement:	req.LikedElement,
+		LikedElementRef:	req.LikedElementRef,
+		Comment:		req.Comment,
 	}
 	swiped, err := s.SwipeRepository.Save(ctx, userID, swipe)
 	if err != nil {
@@ -38,3 +63,13 @@ func (s *SwipeServiceImpl) Create(req *data.CreateSwipeRequest, userID uuid.UUID
 
 	return swiped, nil
 }
+
+func (s *SwipeServiceImpl) FindLikesReceived(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Swipe, error) {
+	swipes, err := s.SwipeRepository.FindLikesReceived(ctx, userID, limit, offset)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to FindLikesReceived: %s", err)
+		return nil, err
+	}
+
+	return swipes, nil
+}
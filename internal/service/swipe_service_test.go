@@ -7,6 +7,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"deals_chatting_app_backend/internal/model"
 	"deals_chatting_app_backend/internal/data"
@@ -14,13 +15,13 @@ import (
 	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
 )
 
-func TestUserService_Create(t *testing.T) {
+func TestSwipeService_Create(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockSwipeRepository(ctrl)
 
-	userService := service.NewSwipeService(mockRepo)
+	userService := service.NewSwipeService(mockRepo, mock_repository.NewMockUserRepository(ctrl))
 	
 	userID := uuid.New()
 	swipedUserID := uuid.New()
@@ -44,3 +45,104 @@ func TestUserService_Create(t *testing.T) {
 	assert.NotNil(t, swipe)
 	assert.Equal(t, expectedSwipe, swipe)
 }
+
+func TestSwipeService_FindLikesReceived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSwipeRepository(ctrl)
+
+	swipeService := service.NewSwipeService(mockRepo, mock_repository.NewMockUserRepository(ctrl))
+
+	userID := uuid.New()
+	ctx := context.Background()
+
+	expectedLikes := []model.Swipe{
+		{
+			UserID:       uuid.New(),
+			SwipedUserID: userID,
+			IsLiked:      true,
+			Comment:      "Hi there",
+		},
+	}
+
+	mockRepo.EXPECT().FindLikesReceived(gomock.Any(), userID, 10, 0).Return(expectedLikes, nil)
+
+	likes, err := swipeService.FindLikesReceived(ctx, userID, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedLikes, likes)
+}
+
+func TestSwipeService_CreateLikedElement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSwipeRepository(ctrl)
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	swipeService := service.NewSwipeService(mockRepo, mockUserRepo)
+
+	userID := uuid.New()
+	swipedUserID := uuid.New()
+	profile := &model.Profile{UserID: swipedUserID, Picture: "photo1.jpg", City: "Jakarta"}
+
+	for name, tc := range map[string]struct {
+		element string
+		ref     string
+		valid   bool
+	}{
+		"photo":          {element: "photo", ref: "photo1.jpg", valid: true},
+		"prompt":         {element: "prompt", ref: "city", valid: true},
+		"other photo":    {element: "photo", ref: "photo2.jpg"},
+		"empty prompt":   {element: "prompt", ref: "religion"},
+		"unknown prompt": {element: "prompt", ref: "password"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := &data.CreateSwipeRequest{
+				SwipedUserID:    swipedUserID.String(),
+				IsLiked:         true,
+				LikedElement:    tc.element,
+				LikedElementRef: tc.ref,
+			}
+
+			mockUserRepo.EXPECT().GetProfileByUserID(gomock.Any(), swipedUserID).Return(profile, nil)
+			if tc.valid {
+				mockRepo.EXPECT().Save(gomock.Any(), userID, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, userID uuid.UUID, swipe model.Swipe) (*model.Swipe, error) {
+						return &swipe, nil
+					})
+			}
+
+			swipe, err := swipeService.Create(req, userID, context.Background())
+
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.ref, swipe.LikedElementRef)
+			} else {
+				assert.ErrorIs(t, err, service.ErrInvalidLikedElement)
+			}
+		})
+	}
+}
+
+func TestSwipeService_CreateLikedElementNoProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock_repository.NewMockUserRepository(ctrl)
+	swipeService := service.NewSwipeService(mock_repository.NewMockSwipeRepository(ctrl), mockUserRepo)
+
+	swipedUserID := uuid.New()
+	req := &data.CreateSwipeRequest{
+		SwipedUserID:    swipedUserID.String(),
+		IsLiked:         true,
+		LikedElement:    "photo",
+		LikedElementRef: "photo1.jpg",
+	}
+
+	mockUserRepo.EXPECT().GetProfileByUserID(gomock.Any(), swipedUserID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := swipeService.Create(req, uuid.New(), context.Background())
+
+	assert.ErrorIs(t, err, service.ErrInvalidLikedElement)
+}
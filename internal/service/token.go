@@ -0,0 +1,66 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Token purposes, signed into the payload so a token made for one use is rejected by the others
+const (
+	tokenPurposeVerifyEmail = "verify"
+	tokenPurposeDataExport  = "export"
+)
+
+// signToken encodes a purpose, an ID and an expiry into a URL safe token signed with secret, so it needs no storage
+func signToken(secret, purpose string, id uuid.UUID, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s|%s|%d", purpose, id, expiresAt.Unix())
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(tokenSignature(secret, payload))
+}
+
+// parseToken returns the ID of a token made by signToken for purpose, if the signature matches and it hasn't expired
+func parseToken(secret, purpose, token string, now time.Time) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return uuid.Nil, fmt.Errorf("malformed token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return uuid.Nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !hmac.Equal(signature, tokenSignature(secret, string(payload))) {
+		return uuid.Nil, fmt.Errorf("bad signature")
+	}
+
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 3 {
+		return uuid.Nil, fmt.Errorf("malformed payload")
+	}
+	if fields[0] != purpose {
+		return uuid.Nil, fmt.Errorf("wrong token purpose")
+	}
+	expiresAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if now.Unix() > expiresAt {
+		return uuid.Nil, fmt.Errorf("token expired")
+	}
+	return uuid.Parse(fields[1])
+}
+
+func tokenSignature(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
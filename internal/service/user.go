@@ -4,19 +4,21 @@ import (
 	"fmt"
 	"context"
 	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
 	"deals_chatting_app_backend/internal/model"
 	"deals_chatting_app_backend/internal/repository"
 
 	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
-	"github.com/spf13/viper"
 	"github.com/google/uuid"
-	gocloak "github.com/Nerzal/gocloak/v13"
+	"github.com/spf13/viper"
 )
 
 type UserService interface {
 	Create(*data.UserRequest, context.Context) (*model.User, error)
-	Login(*data.UserLoginRequest, context.Context) (*string, error)
+	Login(*data.UserLoginRequest, context.Context) (*identity.Token, error)
+	RefreshToken(*data.RefreshTokenRequest, context.Context) (*identity.Token, error)
+	Logout(*data.LogoutRequest, context.Context) error
 	CreateOrUpdateProfile(*data.CreateOrUpdateProfileRequest, uuid.UUID, context.Context) (*model.Profile, error)
 	CreateOrUpdatePreferences(*data.CreateOrUpdatePreferencesRequest, uuid.UUID, context.Context) (*model.Preferences, error)
 	FindAll(ctx context.Context, userID uuid.UUID) ([]model.User, error)
@@ -25,13 +27,15 @@ type UserService interface {
 
 type UserServiceImpl struct {
 	UserRepository  repository.UserRepository
-	Keycloak        *gocloak.GoCloak
+	Identity        identity.IdentityProvider
+	EmailVerification EmailVerificationService
 }
 
-func NewUserService(userRepo repository.UserRepository, keycloak *gocloak.GoCloak, ) UserService {
+func NewUserService(userRepo repository.UserRepository, identityProvider identity.IdentityProvider, emailVerification EmailVerificationService) UserService {
 	return &UserServiceImpl{
 		UserRepository:  userRepo,
-		Keycloak:        keycloak,
+		Identity:        identityProvider,
+		EmailVerification: emailVerification,
 	}
 }
 
@@ -40,37 +44,29 @@ func (s *UserServiceImpl) Create(req *data.UserRequest, ctx context.Context) (*m
 	childCtx, span := otel.Tracer("").Start(ctx, "UserService_CreateUser")
 	defer span.End()
 	
-	keycloakClientId := viper.GetString("KEYCLOAK_CLIENT_ID")
-	keycloakRealm := viper.GetString("KEYCLOAK_REALM")
-	keycloakClientSecret := viper.GetString("KEYCLOAK_CLIENT_SECRET")
-
-	token, err := s.Keycloak.LoginClient(childCtx, keycloakClientId, keycloakClientSecret, keycloakRealm)
-	
+	createdUserID, err := s.Identity.CreateUser(childCtx, identity.User{
+		Username: req.Username,
+		Email:    req.Email,
+		Enabled:  true,
+	})
 	if err != nil {
-		zap.L().Sugar().Errorf("Failed to authenticate: %s", err)
+		zap.L().Sugar().Errorf("Failed to create user: %s", err)
 		return nil, err
 	}
 
-	enabled := true
-	newUser := gocloak.User{
-		Username:        &req.Username,
-		Email:           &req.Email,
-		Enabled:         &enabled,
-		// RequiredActions: &[]string{"VERIFY_EMAIL", "UPDATE_PASSWORD"},
-	}
-
-	createdUserID, err := s.Keycloak.CreateUser(childCtx, token.AccessToken, keycloakRealm, newUser)
-	fmt.Println("kjsdvjbsdvjkbds")
-	fmt.Println(err)
+	// create user password
+	err = s.Identity.SetPassword(childCtx, createdUserID, req.Password)
 	if err != nil {
-		zap.L().Sugar().Errorf("Failed to create user: %s", err)
+		zap.L().Sugar().Errorf("Failed to set user password: %s", err)
+		s.undoCreate(createdUserID, childCtx)
 		return nil, err
 	}
 
-	// create user password
-	err = s.Keycloak.SetPassword(childCtx, token.AccessToken, createdUserID, keycloakRealm, req.Password, false)
+	// every new user gets the default realm role
+	err = s.Identity.AssignRole(childCtx, createdUserID, viper.GetString("KEYCLOAK_DEFAULT_ROLE_NAME"))
 	if err != nil {
-		zap.L().Sugar().Errorf("Failed to set user password: %s", err)
+		zap.L().Sugar().Errorf("Failed to assign default role: %s", err)
+		s.undoCreate(createdUserID, childCtx)
 		return nil, err
 	}
 
@@ -87,45 +83,90 @@ func (s *UserServiceImpl) Create(req *data.UserRequest, ctx context.Context) (*m
 
 	savedUser, err := s.UserRepository.Save(childCtx, user)
 	if err != nil {
-		if err := s.Keycloak.DeleteUser(childCtx, token.AccessToken, viper.GetString("KEYCLOAK_REALM"), createdUserID); err != nil {
-			zap.L().Sugar().Errorf("Failed to delete user: %s", err)
-		}
+		s.undoCreate(createdUserID, childCtx)
 		return nil, err
 	}
 	// Log the saved user information for debugging
 	fmt.Println("Saved user:", savedUser)
 
+	// Signup still succeeds when the mail can't be sent, the user can ask for it again
+	if err := s.EmailVerification.SendVerification(savedUser, childCtx); err != nil {
+		zap.L().Sugar().Errorf("Failed to send verification email: %s", err)
+	}
+
 	return savedUser, nil
 }
 
-func (s *UserServiceImpl) Login(req *data.UserLoginRequest, ctx context.Context) (*string, error) {
+// undoCreate removes the identity provider user of a signup that failed halfway, so it can be retried
+func (s *UserServiceImpl) undoCreate(createdUserID string, ctx context.Context) {
+	if err := s.Identity.DeleteUser(ctx, createdUserID); err != nil {
+		zap.L().Sugar().Errorf("Failed to delete user: %s", err)
+	}
+}
+
+func (s *UserServiceImpl) Login(req *data.UserLoginRequest, ctx context.Context) (*identity.Token, error) {
 	childCtx, span := otel.Tracer("").Start(ctx, "UserService_Login")
 	defer span.End()
 
+	// The password is checked first, so the account state is only revealed to its owner
+	token, err := s.Identity.Login(childCtx, req.Username, req.Password)
+	if err != nil {
+		zap.L().Sugar().Errorf("Failed to authenticate: %s", err)
+		return nil, err
+	}
+
 	user, err := s.UserRepository.FindByUsername(childCtx, req.Username)
 	if err != nil {
 		zap.L().Sugar().Errorf("User doesn't Exist: %s", err)
+		s.endSession(token, childCtx)
 		return nil, err
 	}
-	// check whether the user is active or not
-	if !user.IsActive{
-		zap.L().Sugar().Errorf("User is not Active: %s", err)
-		return nil, fmt.Errorf("user is not active")
+	if user == nil {
+		s.endSession(token, childCtx)
+		return nil, identity.ErrInvalidCredentials
+	}
+	// only active accounts can log in, pending ones have to verify their email first
+	if user.Status != model.AccountStatusActive {
+		zap.L().Sugar().Errorf("User %s is %s", user.ID, user.Status)
+		s.endSession(token, childCtx)
+		return nil, ErrAccountNotActive
+	}
+
+	return token, nil
+}
+
+// endSession logs out a session we won't hand to the caller
+func (s *UserServiceImpl) endSession(token *identity.Token, ctx context.Context) {
+	if err := s.Identity.Logout(ctx, token.RefreshToken); err != nil {
+		zap.L().Sugar().Errorf("Failed to end session: %s", err)
 	}
-	keycloakClientId := viper.GetString("KEYCLOAK_CLIENT_ID")
-	keycloakRealm := viper.GetString("KEYCLOAK_REALM")
-	keycloakClientSecret := viper.GetString("KEYCLOAK_CLIENT_SECRET")
+}
 
-	fmt.Println("viper.GetStringggg", viper.GetString("KEYCLOAK_REALM"))
+func (s *UserServiceImpl) RefreshToken(req *data.RefreshTokenRequest, ctx context.Context) (*identity.Token, error) {
+	childCtx, span := otel.Tracer("").Start(ctx, "UserService_RefreshToken")
+	defer span.End()
 
-	token, err := s.Keycloak.Login(childCtx, keycloakClientId, keycloakClientSecret, keycloakRealm, req.Username, req.Password)
-	fmt.Println("erororoorloggg", err)
+	token, err := s.Identity.Refresh(childCtx, req.RefreshToken)
 	if err != nil {
-		zap.L().Sugar().Errorf("Failed to authenticate: %s", err)
+		zap.L().Sugar().Errorf("Failed to refresh token: %s", err)
 		return nil, err
 	}
 
-	return &token.AccessToken, nil
+	return token, nil
+}
+
+// Logout ends the session of the refresh token so it can no longer be used. Access tokens already
+// issued stay valid until they expire unless KEYCLOAK_INTROSPECT is on.
+func (s *UserServiceImpl) Logout(req *data.LogoutRequest, ctx context.Context) error {
+	childCtx, span := otel.Tracer("").Start(ctx, "UserService_Logout")
+	defer span.End()
+
+	if err := s.Identity.Logout(childCtx, req.RefreshToken); err != nil {
+		zap.L().Sugar().Errorf("Failed to logout: %s", err)
+		return err
+	}
+
+	return nil
 }
 
 
@@ -2,19 +2,21 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"time"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	"github.com/spf13/viper"
 	"github.com/google/uuid"
+	"github.com/spf13/viper"
 
 	"deals_chatting_app_backend/internal/data"
+	"deals_chatting_app_backend/internal/identity"
 	"deals_chatting_app_backend/internal/model"
 	"deals_chatting_app_backend/internal/service"
 	mock_repository "deals_chatting_app_backend/internal/repository/mocks"
-	gocloak "github.com/Nerzal/gocloak/v13"
+	mock_service "deals_chatting_app_backend/internal/service/mocks"
 )
 
 func TestUserService_CreateOrUpdateProfile(t *testing.T) {
@@ -22,9 +24,7 @@ func TestUserService_CreateOrUpdateProfile(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockUserRepository(ctrl)
-	keycloak := gocloak.NewClient(viper.GetString("KEYCLOAK_URL"))
-
-	userService := service.NewUserService(mockRepo, keycloak)
+	userService := service.NewUserService(mockRepo, identity.NewMemory(), mock_service.NewMockEmailVerificationService(ctrl))
 	
 	// Convert date string to time.Time
 	dobStr := "1990-01-01T00:00:00Z"
@@ -68,9 +68,7 @@ func TestUserService_CreateOrUpdatePreferences(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockUserRepository(ctrl)
-	keycloak := gocloak.NewClient(viper.GetString("KEYCLOAK_URL"))
-
-	userService := service.NewUserService(mockRepo, keycloak)
+	userService := service.NewUserService(mockRepo, identity.NewMemory(), mock_service.NewMockEmailVerificationService(ctrl))
 	
 	req := &data.CreateOrUpdatePreferencesRequest{
 		MinAge:   20,
@@ -99,142 +97,167 @@ func TestUserService_CreateOrUpdatePreferences(t *testing.T) {
 	assert.NotNil(t, preferences)
 	assert.Equal(t, expectedPreferences, preferences)
 }
-// func TestUserService_Create(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	defer ctrl.Finish()
-
-// 	mockRepo := mockRepository.NewMockUserRepository(ctrl)
-// 	mockKeycloak := mockService.NewMockGoCloakInterface(ctrl)
-
-// 	userService := service.NewUserService(mockRepo, mockKeycloak)
-
-// 	req := &data.UserRequest{
-// 		Username: "testuser",
-// 		Email:    "test@example.com",
-// 		Password: "password123",
-// 	}
-
-// 	createdUserID := uuid.New().String()
-// 	token := gocloak.JWT{AccessToken: "access_token"}
-// 	newUser := gocloak.User{
-// 		Username: &req.Username,
-// 		Email:    &req.Email,
-// 		Enabled:  gocloak.BoolP(true),
-// 	}
-
-// 	mockKeycloak.EXPECT().LoginClient(gomock.Any(), "client_id", "client_secret", "realm").Return(&token, nil)
-// 	mockKeycloak.EXPECT().CreateUser(gomock.Any(), "access_token", "realm", newUser).Return(createdUserID, nil)
-// 	mockKeycloak.EXPECT().SetPassword(gomock.Any(), "access_token", createdUserID, "realm", req.Password, false).Return(nil)
-
-// 	savedUser := &model.User{
-// 		ID:        uuid.MustParse(createdUserID),
-// 		Username:  req.Username,
-// 		Email:     req.Email,
-// 		Password:  req.Password,
-// 		IsVerified: false,
-// 	}
-// 	mockRepo.EXPECT().Save(gomock.Any(), *savedUser).Return(savedUser, nil)
-
-// 	ctx := context.TODO()
-// 	result, err := userService.Create(req, ctx)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, result)
-// 	assert.Equal(t, savedUser, result)
-// }
-
-// func TestUserService_Create_Failure(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	defer ctrl.Finish()
-
-// 	mockRepo := mockRepository.NewMockUserRepository(ctrl)
-// 	mockKeycloak := mockService.NewMockGoCloakInterface(ctrl)
-
-// 	userService := service.NewUserService(mockRepo, mockKeycloak)
-
-// 	req := &data.UserRequest{
-// 		Username: "testuser",
-// 		Email:    "test@example.com",
-// 		Password: "password123",
-// 	}
-
-// 	token := gocloak.JWT{AccessToken: "access_token"}
-// 	newUser := gocloak.User{
-// 		Username: &req.Username,
-// 		Email:    &req.Email,
-// 		Enabled:  gocloak.BoolP(true),
-// 	}
-
-// 	mockKeycloak.EXPECT().LoginClient(gomock.Any(), "client_id", "client_secret", "realm").Return(&token, nil)
-// 	mockKeycloak.EXPECT().CreateUser(gomock.Any(), "access_token", "realm", newUser).Return("", errors.New("failed to create user"))
-
-// 	ctx := context.TODO()
-// 	result, err := userService.Create(req, ctx)
-
-// 	assert.Error(t, err)
-// 	assert.Nil(t, result)
-// }
-
-// func TestUserService_Login(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	defer ctrl.Finish()
-
-// 	mockRepo := mockRepository.NewMockUserRepository(ctrl)
-// 	mockKeycloak := mockService.NewMockGoCloakInterface(ctrl)
-
-// 	userService := service.NewUserService(mockRepo, mockKeycloak)
-
-// 	req := &data.UserLoginRequest{
-// 		Username: "testuser",
-// 		Password: "password123",
-// 	}
-
-// 	user := &model.User{
-// 		ID:        uuid.New(),
-// 		Username:  "testuser",
-// 		Password:  "password123",
-// 		IsActive:  true,
-// 	}
-// 	token := gocloak.JWT{AccessToken: "access_token"}
-
-// 	mockRepo.EXPECT().FindByUsername(gomock.Any(), req.Username).Return(user, nil)
-// 	mockKeycloak.EXPECT().Login(gomock.Any(), "client_id", "client_secret", "realm", req.Username, req.Password).Return(&token, nil)
-
-// 	ctx := context.TODO()
-// 	result, err := userService.Login(req, ctx)
-
-// 	assert.NoError(t, err)
-// 	assert.NotNil(t, result)
-// 	assert.Equal(t, token.AccessToken, *result)
-// }
-
-// func TestUserService_Login_UserNotActive(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	defer ctrl.Finish()
-
-// 	mockRepo := mockRepository.NewMockUserRepository(ctrl)
-// 	mockKeycloak := mockService.NewMockGoCloakInterface(ctrl)
-
-// 	userService := service.NewUserService(mockRepo, mockKeycloak)
-
-// 	req := &data.UserLoginRequest{
-// 		Username: "testuser",
-// 		Password: "password123",
-// 	}
-
-// 	user := &model.User{
-// 		ID:        uuid.New(),
-// 		Username:  "testuser",
-// 		Password:  "password123",
-// 		IsActive:  false,
-// 	}
-
-// 	mockRepo.EXPECT().FindByUsername(gomock.Any(), req.Username).Return(user, nil)
-
-// 	ctx := context.TODO()
-// 	result, err := userService.Login(req, ctx)
+func TestUserService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	mockEmailVerification := mock_service.NewMockEmailVerificationService(ctrl)
+	identityProvider := identity.NewMemory()
+	viper.Set("KEYCLOAK_DEFAULT_ROLE_NAME", "user")
+
+	userService := service.NewUserService(mockRepo, identityProvider, mockEmailVerification)
+
+	req := &data.UserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, user model.User) (*model.User, error) {
+			return &user, nil
+		})
+	mockEmailVerification.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := userService.Create(req, context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, req.Username, result.Username)
+
+	// The stored user shares its ID with the identity provider and can log in there
+	created, exists := identityProvider.User(result.ID.String())
+	assert.True(t, exists)
+	assert.Equal(t, req.Email, created.Email)
+	assert.Equal(t, []string{"user"}, created.Roles)
+	_, err = identityProvider.Login(context.TODO(), req.Username, req.Password)
+	assert.NoError(t, err)
+}
+
+func TestUserService_Create_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+
+	userService := service.NewUserService(mockRepo, identityProvider, mock_service.NewMockEmailVerificationService(ctrl))
+
+	req := &data.UserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	// Saving fails, so the identity provider user is removed again
+	var createdID uuid.UUID
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, user model.User) (*model.User, error) {
+			createdID = user.ID
+			return nil, errors.New("failed to save user")
+		})
+
+	result, err := userService.Create(req, context.TODO())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	_, exists := identityProvider.User(createdID.String())
+	assert.False(t, exists)
+
+	// The username is free again
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("failed to save user"))
+	_, err = userService.Create(req, context.TODO())
+	assert.NotErrorIs(t, err, identity.ErrUserExists)
+}
+
+// failingSetPassword rejects every password, like an identity provider enforcing a stricter policy
+type failingSetPassword struct {
+	*identity.Memory
+}
+
+func (f failingSetPassword) SetPassword(ctx context.Context, userID, password string) error {
+	return errors.New("invalid password: too short")
+}
+
+func TestUserService_Create_PasswordFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	memory := identity.NewMemory()
+	userService := service.NewUserService(mock_repository.NewMockUserRepository(ctrl), failingSetPassword{Memory: memory}, mock_service.NewMockEmailVerificationService(ctrl))
+
+	req := &data.UserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	_, err := userService.Create(req, context.TODO())
+
+	// The half created identity provider user is removed, so the username is free again
+	assert.Error(t, err)
+	_, err = memory.CreateUser(context.TODO(), identity.User{Username: req.Username, Email: req.Email})
+	assert.NoError(t, err)
+}
+
+func TestUserService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+
+	userService := service.NewUserService(mockRepo, identityProvider, mock_service.NewMockEmailVerificationService(ctrl))
+
+	userID, _ := identityProvider.CreateUser(context.TODO(), identity.User{Username: "testuser", Enabled: true})
+	identityProvider.SetPassword(context.TODO(), userID, "password123")
+
+	user := &model.User{
+		ID:       uuid.MustParse(userID),
+		Username: "testuser",
+		Status:   model.AccountStatusActive,
+	}
+	mockRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(user, nil)
+
+	result, err := userService.Login(&data.UserLoginRequest{Username: "testuser", Password: "password123"}, context.TODO())
+
+	assert.NoError(t, err)
+	claims, err := identityProvider.VerifyToken(context.TODO(), result.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, claims.Subject)
+
+	_, err = userService.Login(&data.UserLoginRequest{Username: "testuser", Password: "wrong"}, context.TODO())
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+}
 
-// 	assert.Error(t, err)
-// 	assert.Nil(t, result)
-// 	assert.Equal(t, "user is not active", err.Error())
-// }
+func TestUserService_Login_UserNotActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockUserRepository(ctrl)
+	identityProvider := identity.NewMemory()
+
+	userService := service.NewUserService(mockRepo, identityProvider, mock_service.NewMockEmailVerificationService(ctrl))
+
+	userID, _ := identityProvider.CreateUser(context.TODO(), identity.User{Username: "testuser", Enabled: true})
+	identityProvider.SetPassword(context.TODO(), userID, "password123")
+	user := &model.User{
+		ID:       uuid.MustParse(userID),
+		Username: "testuser",
+		Status:   model.AccountStatusPending,
+	}
+
+	// A wrong password fails before the account is even looked up
+	_, err := userService.Login(&data.UserLoginRequest{Username: "testuser", Password: "wrong"}, context.TODO())
+	assert.ErrorIs(t, err, identity.ErrInvalidCredentials)
+
+	mockRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(user, nil)
+
+	result, err := userService.Login(&data.UserLoginRequest{Username: "testuser", Password: "password123"}, context.TODO())
+
+	assert.ErrorIs(t, err, service.ErrAccountNotActive)
+	assert.NotContains(t, err.Error(), string(model.AccountStatusPending))
+	assert.Nil(t, result)
+	// The session opened to check the password is closed again
+	assert.Equal(t, 0, identityProvider.Sessions(userID))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
     "strings"
 
@@ -14,10 +15,13 @@ import (
     "go.opentelemetry.io/otel/semconv/v1.4.0"
     
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	gocloak "github.com/Nerzal/gocloak/v13"
     
 	"deals_chatting_app_backend/internal/controller"
-	// "deals_chatting_app_backend/internal/middleware"
+	"deals_chatting_app_backend/internal/identity"
+	"deals_chatting_app_backend/internal/mailer"
+	"deals_chatting_app_backend/internal/ratelimit"
 	"deals_chatting_app_backend/internal/model"
 	"deals_chatting_app_backend/internal/repository"
 	"deals_chatting_app_backend/internal/service"
@@ -27,6 +31,9 @@ import (
 
 )
 
+// dev is parsed along with the other flags in config.InitConfig
+var dev = flag.Bool("dev", false, "use an in-memory identity provider instead of Keycloak")
+
 func main() {
 	fmt.Println("Hello from myapp")
 
@@ -59,36 +66,75 @@ func main() {
 			&model.Profile{},
 			&model.Preferences{},
             &model.Swipe{},
+			&model.PasswordResetToken{},
+			&model.AccountStatusTransition{},
+			&model.AccountDeletion{},
+			&model.DataExport{},
 		)
+		if err := database.MigrateAccountStatus(db); err != nil {
+			logger.Sugar().Fatalf("failed to migrate account status: %v", err)
+		}
 	}
     
 
-	// Keycloak
-    // keycloakURL := strings.TrimSuffix(viper.GetString("KEYCLOAK_URL"), "/")
-    // fmt.Printf("Keycloak URL: %s\n", keycloakURL) // Add this log
-	// keycloak := gocloak.NewClient(keycloakURL)
-    // keycloak.RestyClient().SetDebug((strings.ToUpper(viper.GetString("KEYCLOAK_DEBUG")) == "TRUE"))
-	// Keycloak
-	keycloak := gocloak.NewClient(viper.GetString("KEYCLOAK_URL"))
-	keycloak.RestyClient().SetDebug((strings.ToUpper(viper.GetString("KEYCLOAK_DEBUG")) == "TRUE"))
+	// Identity provider, --dev keeps users and sessions in memory so no Keycloak is needed
+	var identityProvider identity.IdentityProvider
+	if *dev {
+		zap.L().Sugar().Warnf("Running in dev mode with an in-memory identity provider")
+		identityProvider = identity.NewMemory()
+	} else {
+		identityProvider = newKeycloakProvider()
+	}
 
     
 	validator := validator.New()
 
+	// The capture mailer logs reset and verification links, anyone reading the logs could use them
+	var mail mailer.Mailer
+	if viper.GetString("MAILER") == "capture" {
+		if !*dev {
+			logger.Sugar().Fatalf("MAILER capture is only allowed with --dev")
+		}
+		mail = mailer.NewCaptureMailer()
+	} else {
+		mail = mailer.NewSMTPMailer(viper.GetString("SMTP_HOST"), viper.GetInt("SMTP_PORT"), viper.GetString("SMTP_USERNAME"), viper.GetString("SMTP_PASSWORD"), viper.GetString("MAIL_FROM"))
+	}
+	resetLimitWindow := viper.GetDuration("PASSWORD_RESET_LIMIT_WINDOW")
+	resetEmailLimiter := ratelimit.NewMemoryLimiter(viper.GetInt("PASSWORD_RESET_LIMIT_PER_EMAIL"), resetLimitWindow)
+	resetIPLimiter := ratelimit.NewMemoryLimiter(viper.GetInt("PASSWORD_RESET_LIMIT_PER_IP"), resetLimitWindow)
+	verificationLimitWindow := viper.GetDuration("EMAIL_VERIFICATION_LIMIT_WINDOW")
+	verificationEmailLimiter := ratelimit.NewMemoryLimiter(viper.GetInt("EMAIL_VERIFICATION_LIMIT_PER_EMAIL"), verificationLimitWindow)
+	verificationIPLimiter := ratelimit.NewMemoryLimiter(viper.GetInt("EMAIL_VERIFICATION_LIMIT_PER_IP"), verificationLimitWindow)
+
 	// Repositories
 	userRepository := repository.NewUserRepository(db)
     swipeRepository := repository.NewSwipeRepository(db)
+	passwordResetRepository := repository.NewPasswordResetRepository(db)
+	accountDeletionRepository := repository.NewAccountDeletionRepository(db)
+	dataExportRepository := repository.NewDataExportRepository(db)
 
 	// Services
-	userService := service.NewUserService(userRepository, keycloak)
-    swipeService := service.NewSwipeService(swipeRepository)
+	accountService := service.NewAccountService(userRepository, identityProvider, []string{viper.GetString("KEYCLOAK_ADMIN_ROLE_NAME"), viper.GetString("KEYCLOAK_MODERATOR_ROLE_NAME")})
+	emailVerificationService := service.NewEmailVerificationService(userRepository, identityProvider, mail, verificationEmailLimiter, verificationIPLimiter, accountService)
+	userService := service.NewUserService(userRepository, identityProvider, emailVerificationService)
+    swipeService := service.NewSwipeService(swipeRepository, userRepository)
+	passwordResetService := service.NewPasswordResetService(userRepository, passwordResetRepository, identityProvider, mail, resetEmailLimiter, resetIPLimiter)
+	accountDeletionService := service.NewAccountDeletionService(userRepository, accountDeletionRepository, accountService, identityProvider)
+	service.StartAccountPurger(context.Background(), accountDeletionService, viper.GetDuration("ACCOUNT_DELETION_PURGE_INTERVAL"))
+	dataExportJobs := make(chan uuid.UUID, viper.GetInt("DATA_EXPORT_QUEUE_SIZE"))
+	dataExportService := service.NewDataExportService(dataExportRepository, dataExportJobs)
+	service.StartDataExportWorker(context.Background(), dataExportRepository, dataExportService, dataExportJobs, viper.GetDuration("DATA_EXPORT_PURGE_INTERVAL"), viper.GetDuration("DATA_EXPORT_STALE_AFTER"))
 
 	// Controllers
     userController := controller.NewUserController(userService, validator)
 	swipeController := controller.NewSwipeController(swipeService, validator)	
+	passwordResetController := controller.NewPasswordResetController(passwordResetService)
+	emailVerificationController := controller.NewEmailVerificationController(emailVerificationService)
+	accountController := controller.NewAccountController(accountService, accountDeletionService)
+	dataExportController := controller.NewDataExportController(dataExportService)
 
 	// Create a new Gin router instance by calling NewRouter function
-	r := router.NewRouter(keycloak, userController, swipeController, logger) // Use the router instance returned by NewRouter
+	r := router.NewRouter(identityProvider, userController, swipeController, passwordResetController, emailVerificationController, accountController, dataExportController, logger) // Use the router instance returned by NewRouter
 
 	// Middlewares
 	// r.Use(middleware.LoggerMiddleware())
@@ -106,6 +152,27 @@ func main() {
 	}
 }
 
+// newKeycloakProvider connects to the configured realm, access tokens are verified locally against the realm keys
+func newKeycloakProvider() identity.IdentityProvider {
+	keycloak := gocloak.NewClient(viper.GetString("KEYCLOAK_URL"))
+	keycloak.RestyClient().SetDebug((strings.ToUpper(viper.GetString("KEYCLOAK_DEBUG")) == "TRUE"))
+
+	realmURL := fmt.Sprintf("%s/realms/%s", strings.TrimSuffix(viper.GetString("KEYCLOAK_URL"), "/"), viper.GetString("KEYCLOAK_REALM"))
+	issuer := viper.GetString("KEYCLOAK_ISSUER")
+	if issuer == "" {
+		issuer = realmURL
+	}
+	audience := viper.GetString("KEYCLOAK_AUDIENCE")
+	if audience == "" {
+		audience = viper.GetString("KEYCLOAK_CLIENT_ID")
+	}
+	jwks := identity.NewJWKSKeySet(realmURL+"/protocol/openid-connect/certs", viper.GetDuration("KEYCLOAK_JWKS_REFRESH_INTERVAL"))
+	jwks.Start(context.Background())
+	tokenVerifier := identity.NewTokenVerifier(jwks, issuer, audience)
+
+	return identity.NewKeycloak(keycloak, tokenVerifier, viper.GetString("KEYCLOAK_CLIENT_ID"), viper.GetString("KEYCLOAK_CLIENT_SECRET"), viper.GetString("KEYCLOAK_REALM"), viper.GetBool("KEYCLOAK_INTROSPECT"))
+}
+
 func initTracer() (func(ctx context.Context) error, error) {
 	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpoint("localhost:4318"), otlptracehttp.WithInsecure())
 	if err != nil {